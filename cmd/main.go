@@ -2,9 +2,13 @@ package main
 
 import (
 	"context"
+	"os"
+	"os/signal"
 	"project/internal/config"
 	"project/internal/logger"
 	"project/internal/transport"
+	"syscall"
+	"time"
 )
 
 func main() {
@@ -15,25 +19,41 @@ func main() {
 	logger.InitLogger(loggerCfg)
 
 	repoCfg := config.Repo{
-		User:     "admin",
-		Pass:     "admin",
-		Host:     "localhost",
-		Port:     "5432",
-		Database: "postgres",
-		SSLMode:  "disable",
+		User:        "admin",
+		Pass:        "admin",
+		Host:        "localhost",
+		Port:        "5432",
+		Database:    "postgres",
+		SSLMode:     "disable",
+		AutoMigrate: true,
+
+		ApplicationName:  "api",
+		MaxConns:         10,
+		StatementTimeout: 5 * time.Second,
+		QueryTimeout:     10 * time.Second,
 	}
 
 	transportCfg := config.Transport{
-		Host: "127.0.0.1",
-		Port: "8080",
+		Host:            "127.0.0.1",
+		Port:            "8080",
+		ShutdownDelay:   5 * time.Second,
+		ShutdownTimeout: 10 * time.Second,
+	}
+
+	eventsCfg := config.Events{
+		LogSink: true,
 	}
 
 	cfg := config.Config{
 		Repo:      repoCfg,
 		Transport: transportCfg,
+		Events:    eventsCfg,
 	}
 
-	tr, err := transport.NewTransport(context.TODO(), cfg)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	tr, err := transport.NewTransport(ctx, cfg)
 	if err != nil {
 		logger.GetLogger().Err(err).Msg("failed to create new transport")
 
@@ -46,8 +66,18 @@ func main() {
 		}
 	}()
 
-	if err := tr.Run(); err != nil {
-		logger.GetLogger().Err(err).Msg("failed to run transport")
-	}
+	errCh := make(chan error, 1)
 
+	go func() {
+		errCh <- tr.Run()
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			logger.GetLogger().Err(err).Msg("failed to run transport")
+		}
+	case <-ctx.Done():
+		logger.GetLogger().Info().Msg("shutting down")
+	}
 }
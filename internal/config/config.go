@@ -3,4 +3,10 @@ package config
 type Config struct {
 	Transport Transport `yaml:"transport"`
 	Repo      Repo      `yaml:"repo"`
+	Events    Events    `yaml:"events"`
+	Webhooks  Webhooks  `yaml:"webhooks"`
+	Reminders Reminders `yaml:"reminders"`
+	Telegram  Telegram  `yaml:"telegram"`
+	Invites   Invites   `yaml:"invites"`
+	Payouts   Payouts   `yaml:"payouts"`
 }
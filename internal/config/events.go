@@ -0,0 +1,18 @@
+package config
+
+import "time"
+
+type Events struct {
+	PollInterval time.Duration `yaml:"pollInterval"`
+	BatchSize    int           `yaml:"batchSize"`
+	// Lease is how long a claimed batch is hidden from other dispatchers. It
+	// must outlast delivery of a batch to every sink.
+	Lease time.Duration `yaml:"lease"`
+	// LogSink writes every delivered event to the application log.
+	LogSink bool `yaml:"logSink"`
+	// HTTPSinks receive event batches as JSON POST requests.
+	HTTPSinks []string `yaml:"httpSinks"`
+	// ExpiryCheckPeriod is how often subscriptions past their expired date
+	// are deactivated.
+	ExpiryCheckPeriod time.Duration `yaml:"expiryCheckPeriod"`
+}
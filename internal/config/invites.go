@@ -0,0 +1,8 @@
+package config
+
+import "time"
+
+type Invites struct {
+	// TTL is how long an invite token issued after payment stays valid.
+	TTL time.Duration `yaml:"ttl"`
+}
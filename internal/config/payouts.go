@@ -0,0 +1,12 @@
+package config
+
+import "time"
+
+type Payouts struct {
+	// CommissionBasisPoints is the platform share of payments in hundredths
+	// of a percent, 1000 is 10%. Payments keep the rate they were made at.
+	CommissionBasisPoints int `yaml:"commissionBasisPoints"`
+	// HoldPeriod is how long a payment stays on hold before the owner can
+	// request its payout.
+	HoldPeriod time.Duration `yaml:"holdPeriod"`
+}
@@ -0,0 +1,16 @@
+package config
+
+import "time"
+
+const (
+	NotifierLog      = "log"
+	NotifierTelegram = "telegram"
+)
+
+type Reminders struct {
+	// Windows before expiry at which a reminder is sent, e.g. 72h, 24h, 1h.
+	Windows     []time.Duration `yaml:"windows"`
+	CheckPeriod time.Duration   `yaml:"checkPeriod"`
+	// Notifier is either "log" or "telegram".
+	Notifier string `yaml:"notifier"`
+}
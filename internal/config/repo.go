@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type Repo struct {
 	User     string `yaml:"user"`
 	Pass     string `yaml:"password"`
@@ -7,4 +9,32 @@ type Repo struct {
 	Port     string `yaml:"port"`
 	Database string `yaml:"db"`
 	SSLMode  string `yaml:"ssl"`
+	// DSN is a full connection string and takes precedence over the fields
+	// above. When both DSN and Host are empty, the standard PG* environment
+	// variables are used.
+	DSN         string `yaml:"dsn"`
+	AutoMigrate bool   `yaml:"autoMigrate"`
+
+	ApplicationName   string        `yaml:"applicationName"`
+	MaxConns          int32         `yaml:"maxConns"`
+	MinConns          int32         `yaml:"minConns"`
+	MaxConnLifetime   time.Duration `yaml:"maxConnLifetime"`
+	MaxConnIdleTime   time.Duration `yaml:"maxConnIdleTime"`
+	HealthCheckPeriod time.Duration `yaml:"healthCheckPeriod"`
+	// StatementTimeout is enforced by the server for every statement.
+	StatementTimeout time.Duration `yaml:"statementTimeout"`
+	// QueryTimeout bounds every repo call on the client side.
+	QueryTimeout time.Duration `yaml:"queryTimeout"`
+
+	// Replicas serve read-only queries. Credentials, database and pool
+	// settings are shared with the primary.
+	Replicas           []Replica     `yaml:"replicas"`
+	ReplicaCheckPeriod time.Duration `yaml:"replicaCheckPeriod"`
+}
+
+type Replica struct {
+	Host string `yaml:"host"`
+	Port string `yaml:"port"`
+	// DSN takes precedence over Host and Port.
+	DSN string `yaml:"dsn"`
 }
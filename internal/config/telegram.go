@@ -0,0 +1,13 @@
+package config
+
+import "time"
+
+type Telegram struct {
+	Token string `yaml:"token"`
+	// APIURL overrides Bot API address, used to point the client at a fake server.
+	APIURL string `yaml:"apiUrl"`
+	// ManageMembers lets the API invite paid users into chats and ban users
+	// whose subscription expired. The bot must be an admin of every chat.
+	ManageMembers bool          `yaml:"manageMembers"`
+	InviteLinkTTL time.Duration `yaml:"inviteLinkTtl"`
+}
@@ -1,6 +1,16 @@
 package config
 
+import "time"
+
 type Transport struct {
 	Host string `yaml:"host"`
 	Port string `yaml:"port"`
+	// ShutdownDelay is how long readiness reports failure before the server
+	// stops accepting connections, so the orchestrator can drain traffic.
+	ShutdownDelay   time.Duration `yaml:"shutdownDelay"`
+	ShutdownTimeout time.Duration `yaml:"shutdownTimeout"`
+	// OperatorToken is the bearer token of platform operator endpoints, like
+	// payout status updates, age verification and pending refunds. They are
+	// disabled while it is empty.
+	OperatorToken string `yaml:"operatorToken"`
 }
@@ -0,0 +1,11 @@
+package config
+
+import "time"
+
+type Webhooks struct {
+	PollInterval time.Duration `yaml:"pollInterval"`
+	BatchSize    int           `yaml:"batchSize"`
+	// MaxAttempts is how many times a delivery is tried before it is marked failed.
+	MaxAttempts int           `yaml:"maxAttempts"`
+	Timeout     time.Duration `yaml:"timeout"`
+}
@@ -0,0 +1,43 @@
+package events
+
+import (
+	"context"
+	"sync"
+
+	"project/internal/model"
+)
+
+// Broker is a sink that wakes up listeners whenever the dispatcher delivers
+// a batch, so they can read new events without polling the outbox.
+type Broker struct {
+	mu     sync.Mutex
+	notify chan struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		notify: make(chan struct{}),
+	}
+}
+
+func (b *Broker) Name() string {
+	return "broker"
+}
+
+func (b *Broker) Deliver(_ context.Context, _ []model.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	close(b.notify)
+	b.notify = make(chan struct{})
+
+	return nil
+}
+
+// Wait returns channel closed on the next delivered batch.
+func (b *Broker) Wait() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.notify
+}
@@ -0,0 +1,173 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"project/internal/config"
+	"project/internal/logger"
+	"project/internal/model"
+	"project/internal/repo"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 100
+	defaultLease        = 10 * time.Minute
+)
+
+// Dispatcher delivers outbox events to sinks. It implements worker.Worker.
+type Dispatcher struct {
+	repo     repo.Repo
+	sinks    []Sink
+	interval time.Duration
+	batch    int
+	lease    time.Duration
+}
+
+func NewDispatcher(r repo.Repo, cfg config.Events, sinks ...Sink) *Dispatcher {
+	d := &Dispatcher{
+		repo:     r,
+		sinks:    sinks,
+		interval: cfg.PollInterval,
+		batch:    cfg.BatchSize,
+		lease:    cfg.Lease,
+	}
+
+	if d.interval <= 0 {
+		d.interval = defaultPollInterval
+	}
+
+	if d.batch <= 0 {
+		d.batch = defaultBatchSize
+	}
+
+	if d.lease <= 0 {
+		d.lease = defaultLease
+	}
+
+	return d
+}
+
+// AddSink registers sink. It must be called before the dispatcher is started.
+func (d *Dispatcher) AddSink(s Sink) {
+	d.sinks = append(d.sinks, s)
+}
+
+func (d *Dispatcher) Name() string {
+	return "outbox-dispatcher"
+}
+
+func (d *Dispatcher) Run(ctx context.Context) error {
+	t := time.NewTicker(d.interval)
+	defer t.Stop()
+
+	for {
+		// Drain the outbox before waiting for the next tick.
+		for {
+			n, err := d.process(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+
+				logger.GetLogger().Err(err).Msg("failed to process events")
+			}
+
+			if n < d.batch {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// process claims a batch of events and hands it to sinks outside of any
+// transaction. Each sink receives only events it has not accepted yet, so a
+// failing sink does not cause others to see the same event twice. It returns
+// the number of claimed events, or zero if any sink failed, to stop draining
+// until the failed events are due again.
+func (d *Dispatcher) process(ctx context.Context) (int, error) {
+	events, err := d.repo.ClaimEvents(ctx, d.batch, d.lease)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim events. %w", err)
+	}
+
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	var (
+		errs   []error
+		failed = make(map[int64]string)
+	)
+
+	for _, s := range d.sinks {
+		pending := make([]model.Event, 0, len(events))
+		ids := make([]int64, 0, len(events))
+
+		for _, e := range events {
+			if !slices.Contains(e.DeliveredTo, s.Name()) {
+				pending = append(pending, e.Event)
+				ids = append(ids, e.Id)
+			}
+		}
+
+		if len(pending) == 0 {
+			continue
+		}
+
+		if err := s.Deliver(ctx, pending); err != nil {
+			err = fmt.Errorf("sink %s. %w", s.Name(), err)
+			errs = append(errs, err)
+
+			for _, id := range ids {
+				failed[id] = err.Error()
+			}
+
+			continue
+		}
+
+		if err := d.repo.MarkSinkDelivered(ctx, s.Name(), ids); err != nil {
+			// The lease expires and the sink receives these events again.
+			errs = append(errs, fmt.Errorf("failed to mark events delivered to %s. %w", s.Name(), err))
+		}
+	}
+
+	delivered := make([]int64, 0, len(events))
+	reasons := make(map[string][]int64)
+
+	for _, e := range events {
+		if reason, ok := failed[e.Id]; ok {
+			reasons[reason] = append(reasons[reason], e.Id)
+		} else {
+			delivered = append(delivered, e.Id)
+		}
+	}
+
+	if len(delivered) > 0 {
+		if err := d.repo.MarkEventsDelivered(ctx, delivered); err != nil {
+			errs = append(errs, fmt.Errorf("failed to mark events delivered. %w", err))
+		}
+	}
+
+	for reason, ids := range reasons {
+		if err := d.repo.MarkEventsFailed(ctx, ids, reason); err != nil {
+			errs = append(errs, fmt.Errorf("failed to mark events failed. %w", err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return 0, errors.Join(errs...)
+	}
+
+	return len(events), nil
+}
@@ -0,0 +1,90 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"project/internal/logger"
+	"project/internal/model"
+)
+
+const httpSinkTimeout = 10 * time.Second
+
+// Sink receives events from the dispatcher. Delivery is at least once, so a
+// sink may see the same event again and should deduplicate by Event.Id.
+type Sink interface {
+	Name() string
+	Deliver(context.Context, []model.Event) error
+}
+
+type logSink struct{}
+
+func NewLogSink() Sink {
+	return logSink{}
+}
+
+func (logSink) Name() string {
+	return "log"
+}
+
+func (logSink) Deliver(_ context.Context, events []model.Event) error {
+	for _, e := range events {
+		logger.GetLogger().Info().
+			Int64("id", e.Id).
+			Str("type", e.Type).
+			Int("chat_id", e.ChatId).
+			Int("user_id", e.UserId).
+			RawJSON("payload", e.Payload).
+			Msg("event")
+	}
+
+	return nil
+}
+
+type httpSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPSink returns sink that posts each batch to url as a JSON array and
+// treats any non 2xx response as a failure.
+func NewHTTPSink(url string) Sink {
+	return &httpSink{
+		url:    url,
+		client: &http.Client{Timeout: httpSinkTimeout},
+	}
+}
+
+func (s *httpSink) Name() string {
+	return "http:" + s.url
+}
+
+func (s *httpSink) Deliver(ctx context.Context, events []model.Event) error {
+	b, err := json.Marshal(events)
+	if err != nil {
+		return fmt.Errorf("failed to marshal. %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("failed to create request. %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request. %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -1,11 +1,25 @@
 package model
 
 type AddNewChat struct {
-	ChatId      int    `json:"chat_id"`
-	OwnerId     int    `json:"owner_id"`
+	ChatId  int `json:"chat_id"`
+	OwnerId int `json:"owner_id"`
+	// Price is in whole units of Currency, RUB by default. PriceMinor sets
+	// it in minor units instead and takes precedence.
 	Price       int    `json:"price"`
+	PriceMinor  *int64 `json:"price_minor,omitempty"`
+	Currency    string `json:"currency,omitempty"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
+	TrialDays   int    `json:"trial_days"`
+}
+
+// Amount returns the chat price in minor units of Currency.
+func (c AddNewChat) Amount() int64 {
+	if c.PriceMinor != nil {
+		return *c.PriceMinor
+	}
+
+	return MinorUnits(int64(c.Price), c.Currency)
 }
 
 // {
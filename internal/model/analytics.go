@@ -0,0 +1,56 @@
+package model
+
+import "time"
+
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
+type AnalyticsRequest struct {
+	OwnerId int
+	// ChatId limits analytics to one chat of the owner, zero means all.
+	ChatId      int
+	From        time.Time
+	To          time.Time
+	Granularity string
+}
+
+// AnalyticsPoint describes paid subscribers in one period. Active and MRR are
+// taken at the end of the period, or now for the current one.
+type AnalyticsPoint struct {
+	Start             time.Time `json:"start"`
+	ActiveSubscribers int       `json:"active_subscribers"`
+	New               int       `json:"new"`
+	Churned           int       `json:"churned"`
+	// MRR is monthly recurring revenue per currency. Lifetime access is not
+	// recurring and does not count.
+	MRR []Money `json:"mrr"`
+}
+
+type PlanRevenue struct {
+	// PlanId is zero for payments made at the chat price.
+	PlanId   int64  `json:"plan_id"`
+	Name     string `json:"name,omitempty"`
+	Payments int    `json:"payments"`
+	Revenue  Money  `json:"revenue"`
+}
+
+type Analytics struct {
+	OwnerId     int              `json:"owner_id"`
+	ChatId      int              `json:"chat_id,omitempty"`
+	From        time.Time        `json:"from"`
+	To          time.Time        `json:"to"`
+	Granularity string           `json:"granularity"`
+	Series      []AnalyticsPoint `json:"series"`
+	// Subscribed counts users who subscribed in the period, Converted those
+	// of them who have paid since.
+	Subscribed int     `json:"subscribed"`
+	Converted  int     `json:"converted"`
+	Conversion float64 `json:"conversion"`
+	// AvgLifetimeDays is the average time from the first payment to churn
+	// of subscribers who churned in the period.
+	AvgLifetimeDays float64       `json:"avg_lifetime_days"`
+	RevenueByPlan   []PlanRevenue `json:"revenue_by_plan"`
+}
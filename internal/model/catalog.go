@@ -0,0 +1,74 @@
+package model
+
+const (
+	CatalogSortPopular   = "popular"
+	CatalogSortPriceAsc  = "price_asc"
+	CatalogSortPriceDesc = "price_desc"
+	// CatalogSortRelevance orders search results by how well they match the
+	// query. It is the default when Query is set.
+	CatalogSortRelevance = "relevance"
+)
+
+// CatalogRequest lists active chats. All filters are optional.
+type CatalogRequest struct {
+	Query    string
+	Category string
+	// Tags limits chats to those having all of them.
+	Tags []string
+	// Currency is required to sort by price, amounts in different
+	// currencies do not compare.
+	Currency string
+	Sort     string
+	// Cursor is NextCursor of the previous page.
+	Cursor string
+	Limit  int
+	// UserId is the user browsing the catalog, age restricted chats are
+	// hidden unless they verified their age.
+	UserId int
+}
+
+// CatalogCursor is the position after the last chat of a page.
+type CatalogCursor struct {
+	SortKey float64 `json:"k"`
+	ChatId  int     `json:"id"`
+}
+
+// CatalogQuery is CatalogRequest validated for the repo.
+type CatalogQuery struct {
+	Query    string
+	Category string
+	Tags     []string
+	Currency string
+	Sort     string
+	After    *CatalogCursor
+	Limit    int
+	// HiddenRatings excludes chats with these content ratings.
+	HiddenRatings []string
+}
+
+type CatalogChat struct {
+	ChatId        int      `json:"chat_id"`
+	Name          string   `json:"name"`
+	Description   string   `json:"description"`
+	Price         Money    `json:"price"`
+	ContentRating string   `json:"content_rating"`
+	Category      string   `json:"category,omitempty"`
+	Tags          []string `json:"tags"`
+	Subscribers   int      `json:"subscribers"`
+	// SortKey orders chats in the catalog query.
+	SortKey float64 `json:"-"`
+}
+
+type CatalogPage struct {
+	Chats []CatalogChat `json:"chats"`
+	// NextCursor is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ChangeCatalogInfo sets how chat is listed in the catalog.
+type ChangeCatalogInfo struct {
+	ChatId   int      `json:"chat_id"`
+	ActorId  int      `json:"actor_id"`
+	Category string   `json:"category"`
+	Tags     []string `json:"tags"`
+}
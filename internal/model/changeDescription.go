@@ -3,4 +3,5 @@ package model
 type ChangeDescription struct {
 	ChatId      int    `json:"chat_id"`
 	Description string `json:"description"`
+	ActorId     int    `json:"actor_id"`
 }
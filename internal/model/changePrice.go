@@ -2,5 +2,23 @@ package model
 
 type ChangePrice struct {
 	ChatId int `json:"chat_id"`
-	Price  int `json:"price"`
+	// Price is in whole units of the chat currency. PriceMinor sets it in
+	// minor units instead and takes precedence. Currency is optional and
+	// must match the chat currency when given.
+	Price      int    `json:"price"`
+	PriceMinor *int64 `json:"price_minor,omitempty"`
+	Currency   string `json:"currency,omitempty"`
+	ActorId    int    `json:"actor_id"`
+	// Grandfather keeps active subscribers on their current price when they
+	// renew.
+	Grandfather bool `json:"grandfather"`
+}
+
+// Amount returns the new price in minor units of currency.
+func (c ChangePrice) Amount(currency string) int64 {
+	if c.PriceMinor != nil {
+		return *c.PriceMinor
+	}
+
+	return MinorUnits(int64(c.Price), currency)
 }
@@ -1,8 +1,32 @@
 package model
 
+import "time"
+
 type ChatInfo struct {
 	ChatId      int    `json:"chat_id"`
 	Name        string `json:"name"`
 	Description string `json:"description"`
-	Price       int    `json:"price"`
+	// Price is set with SetPrice, which fills the price fields clients see:
+	// WholePrice in whole units as before minor units, and the exact
+	// PriceMinor in Currency.
+	Price           Money     `json:"-"`
+	WholePrice      int64     `json:"price"`
+	PriceMinor      int64     `json:"price_minor"`
+	Currency        string    `json:"currency"`
+	TrialDays       int       `json:"trial_days"`
+	ContentRating   string    `json:"content_rating"`
+	Category        string    `json:"category"`
+	Tags            []string  `json:"tags"`
+	Status          string    `json:"status"`
+	StatusChangedAt time.Time `json:"status_changed_at"`
+	// IsActive is set for chats in the active status.
+	IsActive bool   `json:"is_active"`
+	Plans    []Plan `json:"plans"`
+}
+
+func (c *ChatInfo) SetPrice(price Money) {
+	c.Price = price
+	c.WholePrice = price.MajorUnits()
+	c.PriceMinor = price.Amount
+	c.Currency = price.Currency
 }
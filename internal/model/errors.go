@@ -0,0 +1,12 @@
+package model
+
+import "errors"
+
+// Errors returned by service and repo to be mapped onto transport statuses.
+// Wrap them with fmt.Errorf to add details.
+var (
+	ErrNotFound        = errors.New("not found")
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrConflict        = errors.New("conflict")
+	ErrForbidden       = errors.New("forbidden")
+)
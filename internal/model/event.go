@@ -0,0 +1,124 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EventSubscriptionCreated = "SubscriptionCreated"
+	EventPaymentSucceeded    = "PaymentSucceeded"
+	EventSubscriptionExpired = "SubscriptionExpired"
+	// EventChatDisabled is published along with EventChatStatusChanged when a
+	// chat leaves the active status.
+	EventChatDisabled      = "ChatDisabled"
+	EventChatStatusChanged = "ChatStatusChanged"
+	EventChatTransferred   = "ChatTransferred"
+	EventRatingChanged     = "RatingChanged"
+	EventPriceChanged      = "PriceChanged"
+	EventTrialStarted      = "TrialStarted"
+	EventPaymentRefunded   = "PaymentRefunded"
+	// EventSubscriptionRevoked is published when a refund or chat deletion
+	// ends access early.
+	EventSubscriptionRevoked = "SubscriptionRevoked"
+)
+
+// EventTypes lists every event type that can be published.
+var EventTypes = []string{
+	EventSubscriptionCreated,
+	EventPaymentSucceeded,
+	EventSubscriptionExpired,
+	EventChatDisabled,
+	EventChatStatusChanged,
+	EventChatTransferred,
+	EventRatingChanged,
+	EventPriceChanged,
+	EventTrialStarted,
+	EventPaymentRefunded,
+	EventSubscriptionRevoked,
+}
+
+// SubscriptionEventTypes are event types describing subscription and payment
+// state of a user in a chat.
+var SubscriptionEventTypes = []string{
+	EventSubscriptionCreated,
+	EventPaymentSucceeded,
+	EventSubscriptionExpired,
+	EventTrialStarted,
+	EventPaymentRefunded,
+	EventSubscriptionRevoked,
+}
+
+type Event struct {
+	Id        int64           `json:"id"`
+	Type      string          `json:"type"`
+	ChatId    int             `json:"chat_id"`
+	UserId    int             `json:"user_id,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	// Xid is the transaction that wrote the event. It is set only for
+	// events read from the stream.
+	Xid int64 `json:"-"`
+}
+
+func (e Event) Cursor() EventCursor {
+	return EventCursor{Xid: e.Xid, Id: e.Id}
+}
+
+// EventCursor is a position in the event stream. Ids are taken before commit
+// and become visible out of order, so the stream is ordered by the writing
+// transaction first and reads only transactions older than any in progress.
+type EventCursor struct {
+	Xid int64
+	Id  int64
+}
+
+func (c EventCursor) String() string {
+	return fmt.Sprintf("%d-%d", c.Xid, c.Id)
+}
+
+func ParseEventCursor(s string) (EventCursor, error) {
+	var c EventCursor
+
+	xid, id, ok := strings.Cut(s, "-")
+	if !ok {
+		return c, fmt.Errorf("cursor %q. %w", s, ErrInvalidArgument)
+	}
+
+	var err error
+
+	if c.Xid, err = strconv.ParseInt(xid, 10, 64); err != nil {
+		return c, fmt.Errorf("cursor %q. %w", s, ErrInvalidArgument)
+	}
+
+	if c.Id, err = strconv.ParseInt(id, 10, 64); err != nil {
+		return c, fmt.Errorf("cursor %q. %w", s, ErrInvalidArgument)
+	}
+
+	return c, nil
+}
+
+// OutboxEvent is an event claimed for delivery with names of the sinks that
+// already accepted it.
+type OutboxEvent struct {
+	Event
+	DeliveredTo []string
+}
+
+// EventStream selects events streamed to ActorId. The actor must be a viewer
+// of ChatId and may only ask for events of chats they owned as OwnerId.
+type EventStream struct {
+	ChatId  int
+	OwnerId int
+	ActorId int
+}
+
+type EventFilter struct {
+	ChatId  int
+	OwnerId int
+	// Types limits events to the listed types, empty means all of them.
+	Types []string
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+const (
+	ExportCSV    = "csv"
+	ExportNDJSON = "ndjson"
+)
+
+const (
+	SubscriberActive  = "active"
+	SubscriberTrial   = "trial"
+	SubscriberExpired = "expired"
+)
+
+// SubscriberExport is a subscriber of a chat with totals of their payments.
+type SubscriberExport struct {
+	UserId int `json:"user_id"`
+	// Status is "active", "trial" or "expired".
+	Status      string     `json:"status"`
+	ExpiredDate time.Time  `json:"expired_date"`
+	PlanId      int64      `json:"plan_id,omitempty"`
+	Payments    int        `json:"payments"`
+	Paid        Money      `json:"paid"`
+	LastPaidAt  *time.Time `json:"last_paid_at,omitempty"`
+}
+
+type PaymentsExportRequest struct {
+	OwnerId int
+	// ActorId must be the owner.
+	ActorId int
+	From    time.Time
+	To      time.Time
+}
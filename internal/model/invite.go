@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type InviteToken struct {
+	Token      string     `json:"token"`
+	ChatId     int        `json:"chat_id"`
+	UserId     int        `json:"user_id"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	RedeemedAt *time.Time `json:"redeemed_at,omitempty"`
+}
+
+type RedeemInvite struct {
+	Token  string `json:"token"`
+	UserId int    `json:"user_id"`
+}
+
+type InviteRedemption struct {
+	ChatId int `json:"chat_id"`
+	// InviteLink is a single use Telegram link, set when the bot is configured.
+	InviteLink string `json:"invite_link,omitempty"`
+}
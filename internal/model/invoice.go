@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+const (
+	InvoicePending = "pending"
+	InvoicePaid    = "paid"
+)
+
+// Invoice is a pending Telegram Stars payment. Payload identifies it in
+// pre_checkout_query and successful_payment updates.
+type Invoice struct {
+	Id          int64     `json:"id"`
+	ChatId      int       `json:"chat_id"`
+	UserId      int       `json:"user_id"`
+	PlanId      int64     `json:"plan_id,omitempty"`
+	PromoCode   string    `json:"promo_code,omitempty"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Payload     string    `json:"payload"`
+	ListPrice   Money     `json:"list_price"`
+	Amount      Money     `json:"amount"`
+	Status      string    `json:"status"`
+	Link        string    `json:"link,omitempty"`
+	PaymentId   int64     `json:"payment_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PreCheckoutQuery is pre_checkout_query update forwarded by the bot.
+type PreCheckoutQuery struct {
+	Id             string `json:"id"`
+	UserId         int    `json:"user_id"`
+	Currency       string `json:"currency"`
+	TotalAmount    int64  `json:"total_amount"`
+	InvoicePayload string `json:"invoice_payload"`
+}
+
+type PreCheckoutResult struct {
+	Ok           bool   `json:"ok"`
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// SuccessfulPayment is successful_payment message forwarded by the bot.
+type SuccessfulPayment struct {
+	UserId                  int    `json:"user_id"`
+	Currency                string `json:"currency"`
+	TotalAmount             int64  `json:"total_amount"`
+	InvoicePayload          string `json:"invoice_payload"`
+	TelegramPaymentChargeId string `json:"telegram_payment_charge_id"`
+	ProviderPaymentChargeId string `json:"provider_payment_charge_id,omitempty"`
+}
@@ -0,0 +1,46 @@
+package model
+
+// Chat statuses. Only active chats take new subscribers and payments.
+// Subscribers of a paused chat keep access and their expiry is frozen until
+// the chat is resumed. Subscribers of an archived chat keep access until it
+// runs out. Deleting a chat revokes all remaining access without refunds,
+// which are made through refunds beforehand.
+const (
+	ChatActive   = "active"
+	ChatPaused   = "paused"
+	ChatArchived = "archived"
+	ChatDeleted  = "deleted"
+)
+
+// ChatTransitions lists statuses a chat can move to from each status.
+var ChatTransitions = map[string][]string{
+	ChatActive:   {ChatPaused, ChatArchived, ChatDeleted},
+	ChatPaused:   {ChatActive, ChatArchived, ChatDeleted},
+	ChatArchived: {ChatActive, ChatDeleted},
+}
+
+type ChangeChatStatus struct {
+	ChatId int `json:"chat_id"`
+	// ActorId must be a chat admin, or the owner to delete the chat.
+	ActorId int    `json:"actor_id"`
+	Status  string `json:"status"`
+	// Force deletes chat that still has paid subscribers.
+	Force bool `json:"force,omitempty"`
+}
+
+// ChatStatusChange is the result of a chat transition.
+type ChatStatusChange struct {
+	ChatId    int    `json:"chat_id"`
+	OldStatus string `json:"old_status"`
+	Status    string `json:"status"`
+	// Extended counts subscriptions whose expiry moved when the chat left the
+	// paused status, Revoked those ended by deleting the chat.
+	Extended int `json:"extended,omitempty"`
+	Revoked  int `json:"revoked,omitempty"`
+}
+
+type GetChats struct {
+	OwnerId int `json:"owner_id"`
+	// IncludeArchived lists archived chats as well. Deleted ones never are.
+	IncludeArchived bool `json:"include_archived,omitempty"`
+}
@@ -0,0 +1,72 @@
+package model
+
+import "time"
+
+// Chat member roles. Viewers can see subscribers, admins can also change
+// price and description, owners can manage members and hand the chat over.
+const (
+	RoleOwner  = "owner"
+	RoleAdmin  = "admin"
+	RoleViewer = "viewer"
+)
+
+var roleRanks = map[string]int{
+	RoleViewer: 1,
+	RoleAdmin:  2,
+	RoleOwner:  3,
+}
+
+// HasRole reports whether role grants everything required does.
+func HasRole(role string, required string) bool {
+	return roleRanks[role] > 0 && roleRanks[role] >= roleRanks[required]
+}
+
+type ChatMember struct {
+	ChatId    int       `json:"chat_id"`
+	UserId    int       `json:"user_id"`
+	Role      string    `json:"role"`
+	AddedBy   int       `json:"added_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ChatActor identifies the chat member a request is made by.
+type ChatActor struct {
+	ChatId  int `json:"chat_id"`
+	ActorId int `json:"actor_id"`
+}
+
+type AddChatMember struct {
+	ChatId  int    `json:"chat_id"`
+	ActorId int    `json:"actor_id"`
+	UserId  int    `json:"user_id"`
+	Role    string `json:"role"`
+}
+
+type RemoveChatMember struct {
+	ChatId  int `json:"chat_id"`
+	ActorId int `json:"actor_id"`
+	UserId  int `json:"user_id"`
+}
+
+const (
+	TransferPending   = "pending"
+	TransferAccepted  = "accepted"
+	TransferDeclined  = "declined"
+	TransferCancelled = "cancelled"
+)
+
+type InitiateTransfer struct {
+	ChatId     int `json:"chat_id"`
+	ActorId    int `json:"actor_id"`
+	NewOwnerId int `json:"new_owner_id"`
+}
+
+type ChatTransfer struct {
+	Id          int64      `json:"id"`
+	ChatId      int        `json:"chat_id"`
+	FromOwnerId int        `json:"from_owner_id"`
+	ToOwnerId   int        `json:"to_owner_id"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	CurrencyRUB = "RUB"
+	CurrencyUSD = "USD"
+	CurrencyEUR = "EUR"
+	// CurrencyXTR is Telegram Stars.
+	CurrencyXTR = "XTR"
+
+	DefaultCurrency = CurrencyRUB
+)
+
+// currencyExponents holds the number of minor unit digits of every supported
+// currency.
+var currencyExponents = map[string]int{
+	CurrencyRUB: 2,
+	CurrencyUSD: 2,
+	CurrencyEUR: 2,
+	CurrencyXTR: 0,
+}
+
+func ValidateCurrency(currency string) error {
+	if _, ok := currencyExponents[currency]; !ok {
+		return fmt.Errorf("unsupported currency %q. %w", currency, ErrInvalidArgument)
+	}
+
+	return nil
+}
+
+// Money is an amount in minor units of its currency: kopecks for RUB, cents
+// for USD and EUR, whole stars for XTR.
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+func NewMoney(amount int64, currency string) Money {
+	return Money{
+		Amount:   amount,
+		Currency: currency,
+	}
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, fmt.Errorf("cannot subtract %s from %s. %w", o.Currency, m.Currency, ErrInvalidArgument)
+	}
+
+	return NewMoney(m.Amount-o.Amount, m.Currency), nil
+}
+
+// Percent returns percent of m rounded to the nearest minor unit, halves away
+// from zero: 15% of 0.10 RUB is 0.02 RUB.
+func (m Money) Percent(percent int64) Money {
+	v := m.Amount * percent
+
+	if v < 0 {
+		return NewMoney((v-50)/100, m.Currency)
+	}
+
+	return NewMoney((v+50)/100, m.Currency)
+}
+
+// MinorUnits converts amount of whole units of currency to its minor units.
+func MinorUnits(amount int64, currency string) int64 {
+	for i := 0; i < currencyExponents[currency]; i++ {
+		amount *= 10
+	}
+
+	return amount
+}
+
+// MajorUnits returns the whole units of m, dropping the minor ones.
+func (m Money) MajorUnits() int64 {
+	amount := m.Amount
+
+	for i := 0; i < currencyExponents[m.Currency]; i++ {
+		amount /= 10
+	}
+
+	return amount
+}
+
+// NormalizeCurrency uppercases currency code, empty code stays empty.
+func NormalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
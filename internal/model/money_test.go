@@ -0,0 +1,89 @@
+package model
+
+import "testing"
+
+func TestMoneyPercent(t *testing.T) {
+	tests := []struct {
+		amount  int64
+		percent int64
+		want    int64
+	}{
+		{amount: 1000, percent: 15, want: 150},
+		{amount: 10, percent: 15, want: 2},
+		{amount: 10, percent: 14, want: 1},
+		{amount: 3, percent: 50, want: 2},
+		{amount: -3, percent: 50, want: -2},
+		{amount: 999, percent: 100, want: 999},
+	}
+
+	for _, tt := range tests {
+		got := NewMoney(tt.amount, CurrencyRUB).Percent(tt.percent)
+
+		if got.Amount != tt.want || got.Currency != CurrencyRUB {
+			t.Errorf("%d%% of %d = %d %s, want %d", tt.percent, tt.amount, got.Amount, got.Currency, tt.want)
+		}
+	}
+}
+
+func TestMoneyUnits(t *testing.T) {
+	tests := []struct {
+		whole    int64
+		currency string
+		minor    int64
+	}{
+		{whole: 500, currency: CurrencyRUB, minor: 50000},
+		{whole: 3, currency: CurrencyUSD, minor: 300},
+		{whole: 250, currency: CurrencyXTR, minor: 250},
+		{whole: 0, currency: CurrencyEUR, minor: 0},
+	}
+
+	for _, tt := range tests {
+		if got := MinorUnits(tt.whole, tt.currency); got != tt.minor {
+			t.Errorf("MinorUnits(%d, %s) = %d, want %d", tt.whole, tt.currency, got, tt.minor)
+		}
+
+		if got := NewMoney(tt.minor, tt.currency).MajorUnits(); got != tt.whole {
+			t.Errorf("MajorUnits(%d %s) = %d, want %d", tt.minor, tt.currency, got, tt.whole)
+		}
+	}
+
+	if got := NewMoney(1999, CurrencyRUB).MajorUnits(); got != 19 {
+		t.Errorf("MajorUnits(1999 RUB) = %d, want 19", got)
+	}
+}
+
+func TestPriceAmount(t *testing.T) {
+	minor := int64(1050)
+
+	tests := []struct {
+		name string
+		chat AddNewChat
+		want int64
+	}{
+		{name: "whole rubles", chat: AddNewChat{Price: 500, Currency: CurrencyRUB}, want: 50000},
+		{name: "whole stars", chat: AddNewChat{Price: 500, Currency: CurrencyXTR}, want: 500},
+		{name: "minor units", chat: AddNewChat{Price: 500, PriceMinor: &minor, Currency: CurrencyRUB}, want: 1050},
+	}
+
+	for _, tt := range tests {
+		if got := tt.chat.Amount(); got != tt.want {
+			t.Errorf("%s: AddNewChat.Amount() = %d, want %d", tt.name, got, tt.want)
+		}
+
+		change := ChangePrice{Price: tt.chat.Price, PriceMinor: tt.chat.PriceMinor}
+
+		if got := change.Amount(tt.chat.Currency); got != tt.want {
+			t.Errorf("%s: ChangePrice.Amount() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestChatInfoSetPrice(t *testing.T) {
+	var c ChatInfo
+
+	c.SetPrice(NewMoney(50050, CurrencyRUB))
+
+	if c.WholePrice != 500 || c.PriceMinor != 50050 || c.Currency != CurrencyRUB {
+		t.Fatalf("chat = %+v, want price 500, price_minor 50050 RUB", c)
+	}
+}
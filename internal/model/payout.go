@@ -0,0 +1,102 @@
+package model
+
+import "time"
+
+const (
+	PayoutRequested  = "requested"
+	PayoutProcessing = "processing"
+	PayoutPaid       = "paid"
+	PayoutRejected   = "rejected"
+	PayoutFailed     = "failed"
+)
+
+// PayoutTransitions lists statuses a payout can move to from each status.
+var PayoutTransitions = map[string][]string{
+	PayoutRequested:  {PayoutProcessing, PayoutPaid, PayoutRejected},
+	PayoutProcessing: {PayoutPaid, PayoutFailed},
+}
+
+// Balance is what an owner earned in one currency. Payments count net of
+// refunds and platform commission.
+type Balance struct {
+	Currency string `json:"currency"`
+	// Gross is the sum of payments, Refunded the part of it returned to users.
+	Gross      Money `json:"gross"`
+	Refunded   Money `json:"refunded"`
+	Commission Money `json:"commission"`
+	// OnHold is earned from payments still in the holding period.
+	OnHold Money `json:"on_hold"`
+	// InPayout is requested but not paid out yet.
+	InPayout Money `json:"in_payout"`
+	PaidOut  Money `json:"paid_out"`
+	// Available can be requested for payout. It is negative when refunds
+	// came after the money was paid out.
+	Available Money `json:"available"`
+}
+
+// BalanceTotals are sums of an owner's payments and payouts in one currency.
+type BalanceTotals struct {
+	Currency           string
+	Gross              int64
+	Refunded           int64
+	Cleared            int64
+	ClearedCommission  int64
+	Held               int64
+	HeldCommission     int64
+	PayoutsPaid        int64
+	PayoutsOutstanding int64
+}
+
+type StatementRequest struct {
+	OwnerId int       `json:"owner_id"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+}
+
+const (
+	StatementPayment = "payment"
+	StatementRefund  = "refund"
+	StatementPayout  = "payout"
+)
+
+// StatementEntry is a payment, refund or payout of an owner. Amount is
+// negative for money leaving the owner's balance.
+type StatementEntry struct {
+	Type   string `json:"type"`
+	Id     int64  `json:"id"`
+	ChatId int    `json:"chat_id,omitempty"`
+	UserId int    `json:"user_id,omitempty"`
+	Amount Money  `json:"amount"`
+	// Commission is charged on the part of a payment not refunded.
+	Commission Money     `json:"commission"`
+	Status     string    `json:"status,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type Statement struct {
+	OwnerId int              `json:"owner_id"`
+	From    time.Time        `json:"from"`
+	To      time.Time        `json:"to"`
+	Entries []StatementEntry `json:"entries"`
+}
+
+type PayoutRequest struct {
+	OwnerId int   `json:"owner_id"`
+	Amount  Money `json:"amount"`
+}
+
+type Payout struct {
+	Id        int64     `json:"id"`
+	OwnerId   int       `json:"owner_id"`
+	Amount    Money     `json:"amount"`
+	Status    string    `json:"status"`
+	Reference string    `json:"reference,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type PayoutStatusUpdate struct {
+	Id        int64  `json:"id"`
+	Status    string `json:"status"`
+	Reference string `json:"reference,omitempty"`
+}
@@ -0,0 +1,20 @@
+package model
+
+type Plan struct {
+	Id     int64 `json:"id"`
+	ChatId int   `json:"chat_id"`
+	// ActorId is the chat admin changing the plan.
+	ActorId int    `json:"actor_id,omitempty"`
+	Name    string `json:"name"`
+	// Price currency defaults to the chat currency and must match it.
+	Price Money `json:"price"`
+	// DurationMonths is ignored for lifetime plans.
+	DurationMonths int  `json:"duration_months,omitempty"`
+	Lifetime       bool `json:"lifetime"`
+}
+
+type PlanId struct {
+	Id      int64 `json:"id"`
+	ChatId  int   `json:"chat_id"`
+	ActorId int   `json:"actor_id"`
+}
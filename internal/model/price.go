@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type PriceChange struct {
+	Id       int64  `json:"id"`
+	ChatId   int    `json:"chat_id"`
+	OldPrice *Money `json:"old_price"`
+	NewPrice Money  `json:"new_price"`
+	ActorId  int    `json:"actor_id,omitempty"`
+	// Grandfathered is set when active subscribers kept the old price.
+	Grandfathered bool      `json:"grandfathered"`
+	EffectiveAt   time.Time `json:"effective_at"`
+}
@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+const (
+	DiscountPercent = "percent"
+	DiscountFixed   = "fixed"
+)
+
+type PromoCode struct {
+	ChatId int `json:"chat_id"`
+	// ActorId is the chat admin adding the code.
+	ActorId int    `json:"actor_id,omitempty"`
+	Code    string `json:"code"`
+	// DiscountType is "percent" or "fixed". Fixed discount is in minor units
+	// of the chat currency.
+	DiscountType  string     `json:"discount_type"`
+	DiscountValue int        `json:"discount_value"`
+	ValidFrom     *time.Time `json:"valid_from,omitempty"`
+	ValidUntil    *time.Time `json:"valid_until,omitempty"`
+	// UsageLimit and PerUserLimit are unlimited when zero.
+	UsageLimit       int  `json:"usage_limit,omitempty"`
+	PerUserLimit     int  `json:"per_user_limit,omitempty"`
+	FirstPaymentOnly bool `json:"first_payment_only"`
+	UsedCount        int  `json:"used_count"`
+}
+
+type DeletePromoCode struct {
+	ChatId  int    `json:"chat_id"`
+	ActorId int    `json:"actor_id"`
+	Code    string `json:"code"`
+}
+
+type Pay struct {
+	ChatId int `json:"chat_id"`
+	UserId int `json:"user_id"`
+	// PlanId is optional, without it the chat price buys one month.
+	PlanId    int64  `json:"plan_id,omitempty"`
+	PromoCode string `json:"promo_code,omitempty"`
+}
+
+// Quote is the price a user would pay for a chat right now.
+type Quote struct {
+	PlanId    int64  `json:"plan_id,omitempty"`
+	ListPrice Money  `json:"list_price"`
+	Discount  Money  `json:"discount"`
+	Amount    Money  `json:"amount"`
+	PromoCode string `json:"promo_code,omitempty"`
+	// Grandfathered is set when ListPrice is the subscriber's locked price.
+	Grandfathered bool `json:"grandfathered,omitempty"`
+}
+
+type Payment struct {
+	Id        int64  `json:"id"`
+	ChatId    int    `json:"chat_id"`
+	UserId    int    `json:"user_id"`
+	PlanId    int64  `json:"plan_id,omitempty"`
+	ListPrice Money  `json:"list_price"`
+	Amount    Money  `json:"amount"`
+	PromoCode string `json:"promo_code,omitempty"`
+	// TelegramChargeId is set for payments made in Telegram Stars.
+	TelegramChargeId string `json:"telegram_payment_charge_id,omitempty"`
+	// CommissionBasisPoints is the platform commission rate of the payment.
+	CommissionBasisPoints int `json:"commission_basis_points"`
+	// Refunded is the part of Amount returned to the user, RefundedAt is set
+	// once all of it is.
+	Refunded   Money      `json:"refunded"`
+	RefundedAt *time.Time `json:"refunded_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
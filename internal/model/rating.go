@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Content ratings of chats. Adult chats are age restricted: users who have
+// not verified their age cannot subscribe to them and do not see them in
+// the catalog.
+const (
+	RatingGeneral = "general"
+	RatingMature  = "mature"
+	RatingAdult   = "adult"
+)
+
+var ContentRatings = []string{RatingGeneral, RatingMature, RatingAdult}
+
+// AgeRestrictedRatings need age verification.
+var AgeRestrictedRatings = []string{RatingAdult}
+
+type ChangeRating struct {
+	ChatId  int    `json:"chat_id"`
+	ActorId int    `json:"actor_id"`
+	Rating  string `json:"rating"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// RatingChange is an audit record of a chat rating change.
+type RatingChange struct {
+	Id        int64     `json:"id"`
+	ChatId    int       `json:"chat_id"`
+	OldRating string    `json:"old_rating"`
+	NewRating string    `json:"new_rating"`
+	ActorId   int       `json:"actor_id"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SetAgeVerified struct {
+	UserId   int  `json:"user_id"`
+	Verified bool `json:"verified"`
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+type RefundRequest struct {
+	ChatId    int   `json:"chat_id"`
+	PaymentId int64 `json:"payment_id"`
+	// Amount is in minor units of the payment currency. Zero refunds the
+	// rest of the payment.
+	Amount int64  `json:"amount,omitempty"`
+	Reason string `json:"reason,omitempty"`
+	// ActorId is the chat admin issuing the refund.
+	ActorId int `json:"actor_id"`
+	// RevokeAccess ends the subscription now instead of shortening it by the
+	// refunded share of the period the payment bought.
+	RevokeAccess bool `json:"revoke_access"`
+}
+
+const (
+	// RefundPending is a refund sent to the payment provider and not applied
+	// yet.
+	RefundPending   = "pending"
+	RefundCompleted = "completed"
+	// RefundFailed is a refund the payment provider rejected, Error holds
+	// the reason.
+	RefundFailed = "failed"
+)
+
+// ResolveRefund settles a pending refund by hand once the operator checked
+// it with the payment provider. Status is "completed" or "failed".
+type ResolveRefund struct {
+	RefundId int64  `json:"refund_id"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+type Refund struct {
+	Id            int64      `json:"id"`
+	PaymentId     int64      `json:"payment_id"`
+	ChatId        int        `json:"chat_id"`
+	UserId        int        `json:"user_id"`
+	Amount        Money      `json:"amount"`
+	Reason        string     `json:"reason,omitempty"`
+	ActorId       int        `json:"actor_id,omitempty"`
+	AccessRevoked bool       `json:"access_revoked"`
+	ExpiredDate   *time.Time `json:"expired_date,omitempty"`
+	Status        string     `json:"status"`
+	Error         string     `json:"error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
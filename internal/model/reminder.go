@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+type ExpiryReminder struct {
+	ChatId      int
+	UserId      int
+	ChatName    string
+	ExpiredDate time.Time
+	// Window is the reminder window the subscription fell into.
+	Window time.Duration
+}
@@ -0,0 +1,10 @@
+package model
+
+type JoinRequest struct {
+	ChatId int `json:"chat_id"`
+	UserId int `json:"user_id"`
+}
+
+type JoinRequestResult struct {
+	Approved bool `json:"approved"`
+}
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+const (
+	AccessNone  = "none"
+	AccessTrial = "trial"
+	AccessPaid  = "paid"
+)
+
+type ChangeTrial struct {
+	ChatId    int `json:"chat_id"`
+	ActorId   int `json:"actor_id"`
+	TrialDays int `json:"trial_days"`
+}
+
+type Access struct {
+	// Access is one of "none", "trial" or "paid".
+	Access      string     `json:"access"`
+	ExpiredDate *time.Time `json:"expired_date,omitempty"`
+}
@@ -0,0 +1,86 @@
+package model
+
+import "time"
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+	// WebhookDeliveryCancelled is a delivery dropped when its webhook was
+	// deactivated.
+	WebhookDeliveryCancelled = "cancelled"
+)
+
+type AddWebhook struct {
+	ChatId  int    `json:"chat_id"`
+	OwnerId int    `json:"owner_id"`
+	Url     string `json:"url"`
+	// Events lists event types to deliver, empty means all of them.
+	Events []string `json:"events"`
+}
+
+type Webhook struct {
+	Id        int64     `json:"id"`
+	ChatId    int       `json:"chat_id"`
+	OwnerId   int       `json:"owner_id"`
+	Url       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookId struct {
+	Id      int64 `json:"id"`
+	OwnerId int   `json:"owner_id"`
+}
+
+// OwnerSecret requests the webhook secret of the chat owner.
+type OwnerSecret struct {
+	ChatId  int  `json:"chat_id"`
+	ActorId int  `json:"actor_id"`
+	Rotate  bool `json:"rotate"`
+}
+
+type WebhookSecret struct {
+	Secret string `json:"secret"`
+}
+
+type GetWebhookDeliveries struct {
+	WebhookId int64 `json:"webhook_id"`
+	OwnerId   int   `json:"owner_id"`
+	Limit     int   `json:"limit"`
+}
+
+type Redeliver struct {
+	DeliveryId int64 `json:"delivery_id"`
+	OwnerId    int   `json:"owner_id"`
+}
+
+type WebhookDelivery struct {
+	Id            int64            `json:"id"`
+	WebhookId     int64            `json:"webhook_id"`
+	EventId       int64            `json:"event_id"`
+	EventType     string           `json:"event_type"`
+	Status        string           `json:"status"`
+	Attempts      int              `json:"attempts"`
+	NextAttemptAt time.Time        `json:"next_attempt_at"`
+	CreatedAt     time.Time        `json:"created_at"`
+	DeliveredAt   *time.Time       `json:"delivered_at,omitempty"`
+	Log           []WebhookAttempt `json:"log"`
+}
+
+type WebhookAttempt struct {
+	AttemptedAt    time.Time `json:"attempted_at"`
+	ResponseStatus int       `json:"response_status,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	DurationMs     int       `json:"duration_ms"`
+}
+
+// PendingWebhookDelivery is a delivery claimed by the delivery worker
+// together with everything needed to send it.
+type PendingWebhookDelivery struct {
+	Id       int64
+	Attempts int
+	Url      string
+	Secret   string
+	Event    Event
+}
@@ -0,0 +1,101 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"project/internal/config"
+	"project/internal/logger"
+	"project/internal/model"
+	"project/internal/telegram"
+)
+
+// Notifier delivers messages to subscribers.
+type Notifier interface {
+	NotifyExpiry(context.Context, model.ExpiryReminder) error
+}
+
+// New returns notifier selected by cfg.Notifier, log notifier by default.
+// tg is nil when Telegram is not configured.
+func New(cfg config.Reminders, tg *telegram.Client) (Notifier, error) {
+	switch cfg.Notifier {
+	case "", config.NotifierLog:
+		return NewLog(), nil
+	case config.NotifierTelegram:
+		if tg == nil {
+			return nil, fmt.Errorf("telegram notifier requires bot token")
+		}
+
+		return NewTelegram(tg), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier %q", cfg.Notifier)
+	}
+}
+
+func expiryText(r model.ExpiryReminder) string {
+	return fmt.Sprintf("Ваша подписка на «%s» заканчивается %s.", r.ChatName, r.ExpiredDate.Format("02.01.2006 15:04 MST"))
+}
+
+type logNotifier struct{}
+
+func NewLog() Notifier {
+	return logNotifier{}
+}
+
+func (logNotifier) NotifyExpiry(_ context.Context, r model.ExpiryReminder) error {
+	logger.GetLogger().Info().
+		Int("chat_id", r.ChatId).
+		Int("user_id", r.UserId).
+		Time("expired_date", r.ExpiredDate).
+		Dur("window", r.Window).
+		Msg(expiryText(r))
+
+	return nil
+}
+
+// Fake records reminders in memory.
+type Fake struct {
+	mu        sync.Mutex
+	reminders []model.ExpiryReminder
+	// Err is returned from NotifyExpiry when set.
+	Err error
+}
+
+func (f *Fake) NotifyExpiry(_ context.Context, r model.ExpiryReminder) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Err != nil {
+		return f.Err
+	}
+
+	f.reminders = append(f.reminders, r)
+
+	return nil
+}
+
+// Reminders returns reminders received so far.
+func (f *Fake) Reminders() []model.ExpiryReminder {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]model.ExpiryReminder(nil), f.reminders...)
+}
+
+type telegramNotifier struct {
+	client *telegram.Client
+}
+
+// NewTelegram returns notifier sending private messages through Bot API.
+func NewTelegram(client *telegram.Client) Notifier {
+	return &telegramNotifier{client: client}
+}
+
+func (t *telegramNotifier) NotifyExpiry(ctx context.Context, r model.ExpiryReminder) error {
+	if err := t.client.SendMessage(ctx, r.UserId, expiryText(r)); err != nil {
+		return fmt.Errorf("failed to send message. %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package payment
+
+import (
+	"context"
+
+	"project/internal/model"
+	"project/internal/telegram"
+)
+
+// Provider charges users through an external payment system.
+type Provider interface {
+	// CreateInvoice returns link the user opens to pay invoice.
+	CreateInvoice(context.Context, model.Invoice) (string, error)
+	// AnswerPreCheckout confirms checkout, or rejects it with reason shown
+	// to the user when reason is not empty.
+	AnswerPreCheckout(ctx context.Context, query_id string, reason string) error
+	// Refund returns the whole payment to the user.
+	Refund(context.Context, model.Payment) error
+}
+
+type stars struct {
+	tg *telegram.Client
+}
+
+// NewStars returns provider selling access for Telegram Stars.
+func NewStars(tg *telegram.Client) Provider {
+	return &stars{tg: tg}
+}
+
+func (s *stars) CreateInvoice(ctx context.Context, invoice model.Invoice) (string, error) {
+	return s.tg.CreateInvoiceLink(ctx, invoice.Title, invoice.Description, invoice.Payload, model.CurrencyXTR, invoice.Amount.Amount)
+}
+
+func (s *stars) AnswerPreCheckout(ctx context.Context, query_id string, reason string) error {
+	return s.tg.AnswerPreCheckoutQuery(ctx, query_id, reason == "", reason)
+}
+
+func (s *stars) Refund(ctx context.Context, payment model.Payment) error {
+	return s.tg.RefundStarPayment(ctx, payment.UserId, payment.TelegramChargeId)
+}
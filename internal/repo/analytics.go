@@ -0,0 +1,98 @@
+package repo
+
+// analyticsCTE selects paid periods of chats of owner $1, limited to chat $2
+// unless it is zero, with the first payment and the churn moments of every
+// subscription. A subscription churns when a period ends with no other one
+// continuing it.
+const analyticsCTE = `
+	with chats as (
+		select chat_id from chat where owner_id = $1 and ($2 = 0 or chat_id = $2)
+	), periods as (
+		select p.chat_id, p.user_id, p.period_start, p.period_end
+		from payments p join chats c on c.chat_id = p.chat_id
+		where p.period_end > p.period_start
+	), firsts as (
+		select chat_id, user_id, min(period_start) as first_at
+		from periods
+		group by chat_id, user_id
+	), churns as (
+		select p.chat_id, p.user_id, p.period_end as churned_at
+		from periods p
+		where p.period_end <= now() and not exists (
+			select 1 from periods q
+			where q.chat_id = p.chat_id and q.user_id = p.user_id
+				and q.period_start <= p.period_end and q.period_end > p.period_end
+		)
+	)
+`
+
+// analyticsBuckets splits [$3, $4) into periods of granularity $5. Each
+// period is measured at its end, or now for the current one.
+const analyticsBuckets = `
+	, buckets as (
+		select b as bucket_start, least(b + ('1 ' || $5)::interval, now()) as at
+		from generate_series(date_trunc($5, $3::timestamptz), $4::timestamptz - interval '1 microsecond', ('1 ' || $5)::interval) b
+	)
+`
+
+const getAnalyticsSeriesQuery = analyticsCTE + analyticsBuckets + `
+	select b.bucket_start,
+		(select count(distinct (p.chat_id, p.user_id)) from periods p
+			where p.period_start <= b.at and p.period_end > b.at),
+		(select count(*) from firsts f
+			where f.first_at >= b.bucket_start and f.first_at < b.bucket_start + ('1 ' || $5)::interval),
+		(select count(*) from churns c
+			where c.churned_at >= b.bucket_start and c.churned_at < b.bucket_start + ('1 ' || $5)::interval)
+	from buckets b
+	order by b.bucket_start
+`
+
+// getAnalyticsMRRQuery sums payments earned by owner $1, so revenue of a
+// transferred chat stays with the owner it was paid to.
+const getAnalyticsMRRQuery = `
+	with revenue as (
+		select p.period_start, p.period_end, p.currency, p.duration_months,
+			p.amount - p.refunded_amount as net
+		from payments p
+		where p.owner_id = $1 and ($2 = 0 or p.chat_id = $2)
+			and p.period_end > p.period_start and p.duration_months is not null
+	)
+` + analyticsBuckets + `
+	select b.bucket_start, r.currency, round(sum(r.net::numeric / r.duration_months))::bigint
+	from buckets b join revenue r on r.period_start <= b.at and r.period_end > b.at
+	group by b.bucket_start, r.currency
+	order by b.bucket_start, r.currency
+`
+
+// getConversionQuery counts subscriptions started in [$3, $4) and those of
+// them paid for since.
+const getConversionQuery = `
+	with chats as (
+		select chat_id from chat where owner_id = $1 and ($2 = 0 or chat_id = $2)
+	), subscribed as (
+		select distinct o.chat_id, o.user_id
+		from outbox o join chats c on c.chat_id = o.chat_id
+		where o.event_type in ('SubscriptionCreated', 'TrialStarted') and o.created_at >= $3 and o.created_at < $4
+	)
+	select count(*),
+		count(*) filter (where exists (
+			select 1 from payments p where p.chat_id = s.chat_id and p.user_id = s.user_id
+		))
+	from subscribed s
+`
+
+const getAvgLifetimeQuery = analyticsCTE + `
+	select coalesce(avg(extract(epoch from c.churned_at - f.first_at)) / 86400, 0)::float8
+	from churns c join firsts f on f.chat_id = c.chat_id and f.user_id = c.user_id
+	where c.churned_at >= $3 and c.churned_at < $4
+`
+
+const getRevenueByPlanQuery = `
+	select coalesce(p.plan_id, 0), coalesce(pl.name, ''), p.currency, count(*),
+		sum(p.amount - p.refunded_amount)::bigint
+	from payments p
+		left join plans pl on pl.id = p.plan_id
+	where p.owner_id = $1 and ($2 = 0 or p.chat_id = $2) and p.created_at >= $3 and p.created_at < $4
+	group by 1, 2, 3
+	order by 5 desc
+`
@@ -0,0 +1,55 @@
+package repo
+
+// getCatalogQuery lists active chats matching search query $1 in Russian or
+// English, category $2, all of tags $3 and currency $4, except those rated
+// as any of $10. Chats are ordered by sort key of sort $5, keys grow along
+// the order so pages continue after ($7, $8) when $6 is set.
+const getCatalogQuery = `
+	with q as (
+		select case when $1 = '' then null
+			else websearch_to_tsquery('russian', $1) || websearch_to_tsquery('english', $1)
+		end as query
+	), c as (
+		select c.chat_id, c.name, c.description, c.price, c.currency, c.content_rating, c.category, c.tags,
+			c.subscriber_count as subscribers,
+			coalesce(ts_rank(c.search_vector, q.query), 0) as rank
+		from chat c, q
+		where c.status = 'active'
+			and (q.query is null or c.search_vector @@ q.query)
+			and ($2 = '' or c.category = $2)
+			and (cardinality($3::text[]) = 0 or c.tags @> $3::text[])
+			and ($4 = '' or c.currency = $4)
+			and not c.content_rating = any($10::text[])
+	), k as (
+		select *,
+			case $5
+				when 'price_asc' then price::float8
+				when 'price_desc' then -price::float8
+				when 'relevance' then -rank::float8
+				else -subscribers::float8
+			end as sort_key
+		from c
+	)
+	select chat_id, name, description, price, currency, content_rating, category, tags, subscribers, sort_key
+	from k
+	where not $6 or (sort_key, chat_id) > ($7::float8, $8::bigint)
+	order by sort_key, chat_id
+	limit $9
+`
+
+// refreshSubscriberCountsQuery recounts active subscriptions of every chat,
+// writing only counts that changed.
+const refreshSubscriberCountsQuery = `
+	with counts as (
+		select c.chat_id, count(u.chat_id)::integer as n
+		from chat c left join users u on u.chat_id = c.chat_id and u.is_active
+		group by c.chat_id
+	)
+	update chat c set subscriber_count = counts.n
+	from counts
+	where c.chat_id = counts.chat_id and c.subscriber_count <> counts.n
+`
+
+const changeCatalogInfoQuery = `
+	update chat set category = $2, tags = $3 where chat_id = $1 and status <> 'deleted'
+`
@@ -0,0 +1,26 @@
+package repo
+
+const exportSubscribersQuery = `
+	select u.user_id,
+		case when not u.is_active then 'expired' when u.is_trial then 'trial' else 'active' end,
+		u.expired_date, coalesce(u.plan_id, 0), coalesce(p.payments, 0),
+		coalesce(p.paid, 0), c.currency, p.last_paid_at
+	from users u
+		join chat c on c.chat_id = u.chat_id
+		left join lateral (
+			select count(*) as payments, sum(amount - refunded_amount)::bigint as paid, max(created_at) as last_paid_at
+			from payments
+			where chat_id = u.chat_id and user_id = u.user_id
+		) p on true
+	where u.chat_id = $1
+	order by u.user_id
+`
+
+const exportPaymentsQuery = `
+	select p.id, p.chat_id, p.user_id, coalesce(p.plan_id, 0), p.list_price, p.amount, p.refunded_amount, p.currency,
+		p.commission_bps, coalesce(p.promo_code, ''), coalesce(p.telegram_payment_charge_id, ''), p.refunded_at,
+		p.created_at
+	from payments p
+	where p.owner_id = $1 and p.created_at >= $2 and p.created_at < $3
+	order by p.created_at, p.id
+`
@@ -0,0 +1,37 @@
+package repo
+
+const revokeInviteTokensQuery = `
+	update invite_tokens set expires_at = now()
+	where chat_id = $1 and user_id = $2 and redeemed_at is null and expires_at > now()
+`
+
+const addInviteTokenQuery = `
+	insert into invite_tokens (token, chat_id, user_id, expires_at)
+	values
+	($1, $2, $3, $4)
+`
+
+const getActiveInviteTokenQuery = `
+	select token, chat_id, user_id, expires_at, redeemed_at
+	from invite_tokens
+	where chat_id = $1 and user_id = $2 and redeemed_at is null and expires_at > now()
+	order by created_at desc
+	limit 1
+`
+
+const getInviteTokenQuery = `
+	select token, chat_id, user_id, expires_at, redeemed_at
+	from invite_tokens
+	where token = $1
+	for update
+`
+
+const redeemInviteTokenQuery = `
+	update invite_tokens set redeemed_at = now(), redeemed_by = $2
+	where token = $1 and redeemed_at is null
+`
+
+const releaseInviteTokenQuery = `
+	update invite_tokens set redeemed_at = null, redeemed_by = null
+	where token = $1 and redeemed_by = $2
+`
@@ -0,0 +1,34 @@
+package repo
+
+const getChatQuery = `
+	select` + chatColumns + `from chat where chat_id = $1
+`
+
+const addInvoiceQuery = `
+	insert into invoices (chat_id, user_id, plan_id, promo_code, payload, list_price, amount, currency)
+	values
+	($1, $2, nullif($3, 0), nullif($4, ''), $5, $6, $7, $8)
+	returning id, status, created_at
+`
+
+// getInvoiceQuery locks the invoice so a repeated successful_payment update
+// waits for the first one to commit.
+const getInvoiceQuery = `
+	select id, chat_id, user_id, coalesce(plan_id, 0), coalesce(promo_code, ''), payload,
+		list_price, amount, currency, status, coalesce(payment_id, 0), created_at
+	from invoices
+	where payload = $1
+	for update
+`
+
+const markInvoicePaidQuery = `
+	update invoices set status = 'paid', payment_id = $2 where id = $1
+`
+
+const getPaymentQuery = `
+	select id, chat_id, user_id, coalesce(plan_id, 0), list_price, amount, refunded_amount, currency, commission_bps,
+		coalesce(promo_code, ''), coalesce(telegram_payment_charge_id, ''), refunded_at, created_at
+	from payments
+	where chat_id = $1 and id = $2
+	for update
+`
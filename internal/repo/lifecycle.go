@@ -0,0 +1,57 @@
+package repo
+
+const lockChatQuery = `
+	select` + chatColumns + `from chat where chat_id = $1 for update
+`
+
+const lockChatSharedQuery = `
+	select` + chatColumns + `from chat where chat_id = $1 for share
+`
+
+const setChatStatusQuery = `
+	update chat set status = $2, status_changed_at = now() where chat_id = $1
+`
+
+// extendSubscriptionsQuery moves expiry of active subscriptions of chat $1,
+// except lifetime ones, by the time passed since $2.
+const extendSubscriptionsQuery = `
+	update users set expired_date = expired_date + (now() - $2::timestamptz)
+	where chat_id = $1 and is_active and expired_date < timestamptz '9999-12-31 00:00:00+00'
+`
+
+// extendPaymentPeriodsQuery moves the part of paid periods after $2 by the
+// time passed since $2, keeping them in line with extended subscriptions.
+const extendPaymentPeriodsQuery = `
+	update payments set
+		period_start = case
+			when period_start > $2::timestamptz then period_start + (now() - $2::timestamptz)
+			else period_start
+		end,
+		period_end = period_end + (now() - $2::timestamptz)
+	where chat_id = $1 and period_end > $2::timestamptz and period_end < timestamptz '9999-12-31 00:00:00+00'
+`
+
+const countPaidSubscribersQuery = `
+	select count(*) from users where chat_id = $1 and is_active and not is_trial
+`
+
+const revokeSubscriptionsQuery = `
+	update users set is_active = false, locked_price = null, expired_date = least(expired_date, now())
+	where chat_id = $1 and is_active
+	returning user_id, expired_date
+`
+
+const closePaymentPeriodsQuery = `
+	update payments set
+		period_start = least(period_start, now()),
+		period_end = least(period_end, now())
+	where chat_id = $1 and period_end > now()
+`
+
+const closePlansQuery = `
+	update plans set is_active = false where chat_id = $1 and is_active
+`
+
+const closePromoCodesQuery = `
+	update promo_codes set is_active = false where chat_id = $1 and is_active
+`
@@ -1,17 +1,83 @@
 package repo
 
+// addNewChatQuery reuses the row of a deleted chat with the same id, as
+// Telegram may hand the chat to the bot again. It adds no row if the chat
+// exists and is not deleted.
 const addNewChatQuery = `
-	insert into chat (chat_id, owner_id, name, description, price, is_active)
+	insert into chat (chat_id, owner_id, name, description, price, trial_days, currency)
 	values
-	($1, $2, $3, $4, $5, true)
+	($1, $2, $3, $4, $5, $6, $7)
+	on conflict (chat_id) do update set
+		owner_id = excluded.owner_id,
+		name = excluded.name,
+		description = excluded.description,
+		price = excluded.price,
+		trial_days = excluded.trial_days,
+		currency = excluded.currency,
+		content_rating = default,
+		category = default,
+		tags = default,
+		status = 'active',
+		status_changed_at = now()
+	where chat.status = 'deleted'
 `
 
-const getChatsInfoByOwnerIdQuery = `
-	select chat_id, name, description, price from chat where owner_id = $1
+// The queries below drop what a deleted chat $1 leaves behind before it is
+// added again, so nothing of the previous owner carries over to the new one.
+
+const clearDeletedChatMembersQuery = `
+	delete from chat_members m using chat c
+	where c.chat_id = $1 and c.status = 'deleted' and m.chat_id = c.chat_id
+`
+
+const cancelDeletedChatTransfersQuery = `
+	update chat_transfers t set status = 'cancelled', resolved_at = now()
+	from chat c
+	where c.chat_id = $1 and c.status = 'deleted' and t.chat_id = c.chat_id and t.status = 'pending'
+`
+
+const closeDeletedChatWebhooksQuery = `
+	update webhooks w set is_active = false
+	from chat c
+	where c.chat_id = $1 and c.status = 'deleted' and w.chat_id = c.chat_id and w.is_active
 `
 
-const disableChatQuery = `
-	update chat set is_active = false where chat_id = $1
+// clearDeletedChatSubscriptionsQuery drops subscriptions with their locked
+// prices. Payments stay for the balance of the owner they were paid to.
+const clearDeletedChatSubscriptionsQuery = `
+	delete from users u using chat c
+	where c.chat_id = $1 and c.status = 'deleted' and u.chat_id = c.chat_id
+`
+
+const clearDeletedChatTrialsQuery = `
+	delete from trials t using chat c
+	where c.chat_id = $1 and c.status = 'deleted' and t.chat_id = c.chat_id
+`
+
+const clearDeletedChatPriceHistoryQuery = `
+	delete from price_changes p using chat c
+	where c.chat_id = $1 and c.status = 'deleted' and p.chat_id = c.chat_id
+`
+
+const expireDeletedChatInvitesQuery = `
+	update invite_tokens i set expires_at = now()
+	from chat c
+	where c.chat_id = $1 and c.status = 'deleted' and i.chat_id = c.chat_id
+		and i.redeemed_at is null and i.expires_at > now()
+`
+
+const chatColumns = `
+	chat_id, name, description, price, currency, trial_days, content_rating, category, tags, status,
+	status_changed_at, status = 'active'
+`
+
+// getChatsInfoByOwnerIdQuery lists chats of owner $1 except deleted ones and,
+// unless $2 is set, archived ones.
+const getChatsInfoByOwnerIdQuery = `
+	select` + chatColumns + `
+	from chat
+	where owner_id = $1 and status <> 'deleted' and ($2 or status <> 'archived')
+	order by chat_id
 `
 
 const changeDescriptionQuery = `
@@ -22,6 +88,14 @@ const changePriceQuery = `
 	update chat set price = $1 where chat_id = $2
 `
 
+const changeTrialQuery = `
+	update chat set trial_days = $1 where chat_id = $2
+`
+
+const getChatTrialDaysQuery = `
+	select trial_days from chat where chat_id = $1
+`
+
 const getAllSlavesQuery = `
 	select user_id from users where chat_id = $1
 `
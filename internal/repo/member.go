@@ -0,0 +1,83 @@
+package repo
+
+const addOwnerMemberQuery = `
+	insert into chat_members (chat_id, user_id, role)
+	values
+	($1, $2, 'owner')
+	on conflict (chat_id, user_id) do update set role = 'owner'
+`
+
+// addChatMemberQuery adds member or changes role of an existing one. The
+// owner's role is changed only by a transfer.
+const addChatMemberQuery = `
+	insert into chat_members (chat_id, user_id, role, added_by)
+	values
+	($1, $2, $3, nullif($4, 0))
+	on conflict (chat_id, user_id) do update set role = excluded.role, added_by = excluded.added_by
+	where chat_members.role <> 'owner'
+	returning created_at
+`
+
+const getChatMembersQuery = `
+	select chat_id, user_id, role, coalesce(added_by, 0), created_at
+	from chat_members
+	where chat_id = $1
+	order by created_at, user_id
+`
+
+const getChatMemberRoleQuery = `
+	select role from chat_members where chat_id = $1 and user_id = $2
+`
+
+const removeChatMemberQuery = `
+	delete from chat_members where chat_id = $1 and user_id = $2 and role <> 'owner'
+`
+
+const transferColumns = `
+	id, chat_id, from_owner_id, to_owner_id, status, created_at, resolved_at
+`
+
+const cancelPendingTransferQuery = `
+	update chat_transfers set status = 'cancelled', resolved_at = now()
+	where chat_id = $1 and status = 'pending'
+`
+
+const addTransferQuery = `
+	insert into chat_transfers (chat_id, from_owner_id, to_owner_id)
+	values
+	($1, $2, $3)
+	returning` + transferColumns
+
+const getPendingTransferQuery = `
+	select` + transferColumns + `
+	from chat_transfers
+	where chat_id = $1 and status = 'pending'
+	for update
+`
+
+const getPendingTransfersQuery = `
+	select` + transferColumns + `
+	from chat_transfers
+	where to_owner_id = $1 and status = 'pending'
+	order by created_at
+`
+
+const resolveTransferQuery = `
+	update chat_transfers set status = $2, resolved_at = now()
+	where id = $1 and status = 'pending'
+	returning resolved_at
+`
+
+const setChatOwnerQuery = `
+	update chat set owner_id = $2 where chat_id = $1
+`
+
+const removeOwnerMemberQuery = `
+	delete from chat_members where chat_id = $1 and role = 'owner'
+`
+
+// deactivateOwnerWebhooksQuery stops webhooks the previous owner set up for
+// the chat from receiving its events.
+const deactivateOwnerWebhooksQuery = `
+	update webhooks set is_active = false where chat_id = $1 and owner_id = $2 and is_active
+`
@@ -0,0 +1,194 @@
+package repo
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"project/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+// migrationsLockId is the advisory lock key taken while migrations are applied,
+// so several instances starting at once do not race each other.
+const migrationsLockId = 7_300_426_001
+
+const createMigrationsTableQuery = `
+	create table if not exists schema_migrations (
+		version    text primary key,
+		applied_at timestamptz not null default now()
+	)
+`
+
+const getAppliedMigrationsQuery = `
+	select version from schema_migrations
+`
+
+const addMigrationQuery = `
+	insert into schema_migrations (version) values ($1)
+`
+
+type migration struct {
+	version string
+	query   string
+}
+
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrationsFS, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations dir. %w", err)
+	}
+
+	migrations := make([]migration, 0, len(entries))
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".sql") {
+			continue
+		}
+
+		b, err := migrationsFS.ReadFile("migrations/" + e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s. %w", e.Name(), err)
+		}
+
+		migrations = append(migrations, migration{
+			version: strings.TrimSuffix(e.Name(), ".sql"),
+			query:   string(b),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	return migrations, nil
+}
+
+type querier interface {
+	Query(context.Context, string, ...any) (pgx.Rows, error)
+}
+
+func appliedMigrations(ctx context.Context, q querier) (map[string]bool, error) {
+	rows, err := q.Query(ctx, getAppliedMigrationsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query. %w", err)
+	}
+
+	versions, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan rows. %w", err)
+	}
+
+	applied := make(map[string]bool, len(versions))
+
+	for _, v := range versions {
+		applied[v] = true
+	}
+
+	return applied, nil
+}
+
+// migrate applies every embedded migration that is not recorded in schema_migrations yet.
+func migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection. %w", err)
+	}
+	defer conn.Release()
+
+	// Migrations and waiting for another instance applying them may take
+	// longer than the statement timeout configured for the pool.
+	if _, err := conn.Exec(ctx, "set statement_timeout = 0"); err != nil {
+		return fmt.Errorf("failed to disable statement timeout. %w", err)
+	}
+
+	defer func() {
+		if _, err := conn.Exec(context.Background(), "reset statement_timeout"); err != nil {
+			logger.GetLogger().Err(err).Msg("failed to reset statement timeout")
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, "select pg_advisory_lock($1)", migrationsLockId); err != nil {
+		return fmt.Errorf("failed to take migrations lock. %w", err)
+	}
+
+	defer func() {
+		if _, err := conn.Exec(context.Background(), "select pg_advisory_unlock($1)", migrationsLockId); err != nil {
+			logger.GetLogger().Err(err).Msg("failed to release migrations lock")
+		}
+	}()
+
+	if _, err := conn.Exec(ctx, createMigrationsTableQuery); err != nil {
+		return fmt.Errorf("failed to create migrations table. %w", err)
+	}
+
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+
+		err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			if _, err := tx.Exec(ctx, m.query); err != nil {
+				return fmt.Errorf("failed to execute query. %w", err)
+			}
+
+			if _, err := tx.Exec(ctx, addMigrationQuery, m.version); err != nil {
+				return fmt.Errorf("failed to record migration. %w", err)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to apply migration %s. %w", m.version, err)
+		}
+
+		logger.GetLogger().Info().Str("version", m.version).Msg("applied migration")
+	}
+
+	return nil
+}
+
+// checkMigrations returns an error if any embedded migration has not been applied.
+func checkMigrations(ctx context.Context, pool *pgxpool.Pool) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	applied, err := appliedMigrations(ctx, pool)
+	if err != nil {
+		return err
+	}
+
+	pending := make([]string, 0)
+
+	for _, m := range migrations {
+		if !applied[m.version] {
+			pending = append(pending, m.version)
+		}
+	}
+
+	if len(pending) > 0 {
+		return fmt.Errorf("pending migrations: %s", strings.Join(pending, ", "))
+	}
+
+	return nil
+}
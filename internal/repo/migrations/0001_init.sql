@@ -0,0 +1,20 @@
+create table if not exists chat (
+	chat_id     bigint primary key,
+	owner_id    bigint not null,
+	name        text not null default '',
+	description text not null default '',
+	price       integer not null default 0,
+	is_active   boolean not null default true
+);
+
+create index if not exists chat_owner_id_idx on chat (owner_id);
+
+create table if not exists users (
+	chat_id      bigint not null,
+	user_id      bigint not null,
+	is_active    boolean not null default false,
+	expired_date timestamptz not null,
+	primary key (chat_id, user_id)
+);
+
+create index if not exists users_user_id_idx on users (user_id);
@@ -0,0 +1,14 @@
+create table if not exists outbox (
+	id              bigserial primary key,
+	event_type      text not null,
+	chat_id         bigint not null,
+	user_id         bigint,
+	payload         jsonb not null default '{}',
+	created_at      timestamptz not null default now(),
+	attempts        integer not null default 0,
+	next_attempt_at timestamptz not null default now(),
+	delivered_at    timestamptz,
+	last_error      text
+);
+
+create index if not exists outbox_pending_idx on outbox (next_attempt_at) where delivered_at is null;
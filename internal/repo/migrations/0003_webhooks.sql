@@ -0,0 +1,42 @@
+create table if not exists owner_secrets (
+	owner_id   bigint primary key,
+	secret     text not null,
+	created_at timestamptz not null default now()
+);
+
+create table if not exists webhooks (
+	id         bigserial primary key,
+	chat_id    bigint not null references chat (chat_id) on delete cascade,
+	owner_id   bigint not null,
+	url        text not null,
+	events     text[] not null default '{}',
+	is_active  boolean not null default true,
+	created_at timestamptz not null default now()
+);
+
+create index if not exists webhooks_chat_id_idx on webhooks (chat_id) where is_active;
+
+create table if not exists webhook_deliveries (
+	id              bigserial primary key,
+	webhook_id      bigint not null references webhooks (id) on delete cascade,
+	event_id        bigint not null references outbox (id),
+	status          text not null default 'pending',
+	attempts        integer not null default 0,
+	next_attempt_at timestamptz not null default now(),
+	created_at      timestamptz not null default now(),
+	delivered_at    timestamptz,
+	unique (webhook_id, event_id)
+);
+
+create index if not exists webhook_deliveries_due_idx on webhook_deliveries (next_attempt_at) where status = 'pending';
+
+create table if not exists webhook_delivery_attempts (
+	id              bigserial primary key,
+	delivery_id     bigint not null references webhook_deliveries (id) on delete cascade,
+	attempted_at    timestamptz not null default now(),
+	response_status integer,
+	error           text,
+	duration_ms     integer not null default 0
+);
+
+create index if not exists webhook_delivery_attempts_delivery_id_idx on webhook_delivery_attempts (delivery_id);
@@ -0,0 +1,8 @@
+create table if not exists expiry_reminders (
+	chat_id        bigint not null,
+	user_id        bigint not null,
+	window_seconds integer not null,
+	expired_date   timestamptz not null,
+	sent_at        timestamptz not null default now(),
+	primary key (chat_id, user_id, window_seconds, expired_date)
+);
@@ -0,0 +1,11 @@
+create table if not exists invite_tokens (
+	token       text primary key,
+	chat_id     bigint not null,
+	user_id     bigint not null,
+	created_at  timestamptz not null default now(),
+	expires_at  timestamptz not null,
+	redeemed_at timestamptz,
+	redeemed_by bigint
+);
+
+create index if not exists invite_tokens_subscription_idx on invite_tokens (chat_id, user_id);
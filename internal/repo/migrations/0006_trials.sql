@@ -0,0 +1,12 @@
+alter table chat add column if not exists trial_days integer not null default 0;
+
+alter table users add column if not exists is_trial boolean not null default false;
+
+-- One row per user and chat that ever got a trial, kept even if the
+-- subscription itself is removed, so a trial cannot be taken twice.
+create table if not exists trials (
+	chat_id    bigint not null,
+	user_id    bigint not null,
+	started_at timestamptz not null default now(),
+	primary key (chat_id, user_id)
+);
@@ -0,0 +1,42 @@
+create table if not exists payments (
+	id         bigserial primary key,
+	chat_id    bigint not null,
+	user_id    bigint not null,
+	-- list_price is the chat price when paid, amount is what was charged.
+	list_price integer not null,
+	amount     integer not null,
+	promo_code text,
+	created_at timestamptz not null default now()
+);
+
+create index if not exists payments_chat_id_idx on payments (chat_id, created_at);
+create index if not exists payments_subscription_idx on payments (chat_id, user_id);
+
+create table if not exists promo_codes (
+	chat_id            bigint not null references chat (chat_id) on delete cascade,
+	code               text not null,
+	discount_type      text not null check (discount_type in ('percent', 'fixed')),
+	discount_value     integer not null check (discount_value > 0),
+	valid_from         timestamptz,
+	valid_until        timestamptz,
+	usage_limit        integer,
+	per_user_limit     integer,
+	first_payment_only boolean not null default false,
+	used_count         integer not null default 0,
+	is_active          boolean not null default true,
+	created_at         timestamptz not null default now(),
+	primary key (chat_id, code)
+);
+
+create table if not exists promo_redemptions (
+	id         bigserial primary key,
+	chat_id    bigint not null,
+	code       text not null,
+	user_id    bigint not null,
+	payment_id bigint not null references payments (id),
+	discount   integer not null,
+	created_at timestamptz not null default now(),
+	foreign key (chat_id, code) references promo_codes (chat_id, code) on delete cascade
+);
+
+create index if not exists promo_redemptions_user_idx on promo_redemptions (chat_id, code, user_id);
@@ -0,0 +1,16 @@
+create table if not exists plans (
+	id              bigserial primary key,
+	chat_id         bigint not null references chat (chat_id) on delete cascade,
+	name            text not null,
+	price           integer not null check (price >= 0),
+	-- duration_months is null for lifetime plans.
+	duration_months integer check (duration_months > 0),
+	is_active       boolean not null default true,
+	created_at      timestamptz not null default now()
+);
+
+create index if not exists plans_chat_id_idx on plans (chat_id) where is_active;
+
+alter table users add column if not exists plan_id bigint references plans (id);
+
+alter table payments add column if not exists plan_id bigint references plans (id);
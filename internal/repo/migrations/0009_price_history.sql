@@ -0,0 +1,19 @@
+create table if not exists price_changes (
+	id            bigserial primary key,
+	chat_id       bigint not null references chat (chat_id) on delete cascade,
+	-- old_price is null for the price a chat was created with.
+	old_price     integer,
+	new_price     integer not null,
+	actor_id      bigint,
+	grandfathered boolean not null default false,
+	effective_at  timestamptz not null default now()
+);
+
+create index if not exists price_changes_chat_id_idx on price_changes (chat_id, effective_at);
+
+insert into price_changes (chat_id, new_price, actor_id)
+select chat_id, price, owner_id from chat;
+
+-- locked_price is the price an active subscriber renews at after a
+-- grandfathered price change.
+alter table users add column if not exists locked_price integer;
@@ -0,0 +1,5 @@
+-- Amounts are stored in minor units of the chat currency. Chats created
+-- before currencies were introduced are priced in rubles.
+alter table chat add column if not exists currency text not null default 'RUB';
+
+alter table payments add column if not exists currency text not null default 'RUB';
@@ -0,0 +1,19 @@
+create table if not exists invoices (
+	id         bigserial primary key,
+	chat_id    bigint not null references chat (chat_id) on delete cascade,
+	user_id    bigint not null,
+	plan_id    bigint references plans (id),
+	promo_code text,
+	-- payload is sent to Telegram and comes back in payment updates.
+	payload    text not null unique,
+	list_price integer not null,
+	amount     integer not null,
+	currency   text not null,
+	status     text not null default 'pending' check (status in ('pending', 'paid')),
+	payment_id bigint references payments (id),
+	created_at timestamptz not null default now()
+);
+
+alter table payments add column if not exists telegram_payment_charge_id text unique;
+
+alter table payments add column if not exists refunded_at timestamptz;
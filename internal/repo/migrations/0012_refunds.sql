@@ -0,0 +1,31 @@
+-- duration_months is what the payment bought, null for lifetime access.
+alter table payments add column if not exists duration_months integer;
+
+update payments p set duration_months = coalesce(
+	(select duration_months from plans where id = p.plan_id),
+	case when p.plan_id is null then 1 end
+);
+
+alter table payments add column if not exists refunded_amount integer not null default 0;
+
+update payments set refunded_amount = amount where refunded_at is not null;
+
+create table if not exists refunds (
+	id             bigserial primary key,
+	payment_id     bigint not null references payments (id),
+	chat_id        bigint not null,
+	user_id        bigint not null,
+	amount         integer not null check (amount > 0),
+	currency       text not null,
+	reason         text not null default '',
+	actor_id       bigint,
+	access_revoked boolean not null default false,
+	-- expired_date is the subscription end after the refund.
+	expired_date   timestamptz,
+	created_at     timestamptz not null default now()
+);
+
+create index if not exists refunds_chat_id_idx on refunds (chat_id, created_at);
+
+insert into refunds (payment_id, chat_id, user_id, amount, currency, created_at)
+select id, chat_id, user_id, amount, currency, refunded_at from payments where refunded_at is not null;
@@ -0,0 +1,17 @@
+-- commission_bps is the platform commission rate a payment was made at.
+alter table payments add column if not exists commission_bps integer not null default 0;
+
+create table if not exists payouts (
+	id         bigserial primary key,
+	owner_id   bigint not null,
+	amount     bigint not null check (amount > 0),
+	currency   text not null,
+	status     text not null default 'requested'
+		check (status in ('requested', 'processing', 'paid', 'rejected', 'failed')),
+	-- reference identifies the transfer in the payment system once it is made.
+	reference  text not null default '',
+	created_at timestamptz not null default now(),
+	updated_at timestamptz not null default now()
+);
+
+create index if not exists payouts_owner_id_idx on payouts (owner_id, created_at);
@@ -0,0 +1,11 @@
+-- period_start and period_end are the access a payment paid for. They feed
+-- analytics and are cut short when a refund shortens the subscription.
+alter table payments add column if not exists period_start timestamptz;
+alter table payments add column if not exists period_end timestamptz;
+
+update payments set
+	period_start = created_at,
+	period_end = coalesce(created_at + make_interval(months => duration_months), timestamptz '9999-12-31 00:00:00+00')
+where period_start is null;
+
+create index if not exists payments_period_idx on payments (chat_id, user_id, period_end);
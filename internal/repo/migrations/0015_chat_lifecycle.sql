@@ -0,0 +1,9 @@
+-- status replaces is_active. Paused chats freeze expiry of their
+-- subscriptions from status_changed_at until they are resumed.
+alter table chat add column if not exists status text not null default 'active'
+	check (status in ('active', 'paused', 'archived', 'deleted'));
+alter table chat add column if not exists status_changed_at timestamptz not null default now();
+
+update chat set status = 'archived' where not is_active;
+
+alter table chat drop column if exists is_active;
@@ -0,0 +1,39 @@
+create table if not exists chat_members (
+	chat_id    bigint not null references chat (chat_id) on delete cascade,
+	user_id    bigint not null,
+	role       text not null check (role in ('owner', 'admin', 'viewer')),
+	added_by   bigint,
+	created_at timestamptz not null default now(),
+	primary key (chat_id, user_id)
+);
+
+create unique index if not exists chat_members_owner_idx on chat_members (chat_id) where role = 'owner';
+create index if not exists chat_members_user_id_idx on chat_members (user_id);
+
+insert into chat_members (chat_id, user_id, role)
+select chat_id, owner_id, 'owner' from chat
+on conflict do nothing;
+
+create table if not exists chat_transfers (
+	id            bigserial primary key,
+	chat_id       bigint not null references chat (chat_id) on delete cascade,
+	from_owner_id bigint not null,
+	to_owner_id   bigint not null,
+	status        text not null default 'pending'
+		check (status in ('pending', 'accepted', 'declined', 'cancelled')),
+	created_at    timestamptz not null default now(),
+	resolved_at   timestamptz
+);
+
+create unique index if not exists chat_transfers_pending_idx on chat_transfers (chat_id) where status = 'pending';
+create index if not exists chat_transfers_to_owner_id_idx on chat_transfers (to_owner_id) where status = 'pending';
+
+-- owner_id is the owner a payment was earned by, so balances stay with the
+-- seller after a chat changes hands.
+alter table payments add column if not exists owner_id bigint;
+
+update payments p set owner_id = c.owner_id from chat c where c.chat_id = p.chat_id and p.owner_id is null;
+
+alter table payments alter column owner_id set not null;
+
+create index if not exists payments_owner_id_idx on payments (owner_id, created_at);
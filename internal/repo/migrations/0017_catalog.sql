@@ -0,0 +1,16 @@
+alter table chat add column if not exists category text not null default '';
+alter table chat add column if not exists tags text[] not null default '{}';
+
+-- search_vector indexes name and description in both Russian and English,
+-- name ranking above description.
+alter table chat add column if not exists search_vector tsvector generated always as (
+	setweight(to_tsvector('russian', name), 'A') ||
+	setweight(to_tsvector('english', name), 'A') ||
+	setweight(to_tsvector('russian', description), 'B') ||
+	setweight(to_tsvector('english', description), 'B')
+) stored;
+
+create index if not exists chat_search_vector_idx on chat using gin (search_vector) where status = 'active';
+create index if not exists chat_tags_idx on chat using gin (tags) where status = 'active';
+create index if not exists chat_category_idx on chat (category) where status = 'active';
+create index if not exists users_active_chat_id_idx on users (chat_id) where is_active;
@@ -0,0 +1,22 @@
+alter table chat add column if not exists content_rating text not null default 'general'
+	check (content_rating in ('general', 'mature', 'adult'));
+
+create table if not exists rating_changes (
+	id         bigserial primary key,
+	chat_id    bigint not null references chat (chat_id) on delete cascade,
+	old_rating text not null,
+	new_rating text not null,
+	actor_id   bigint not null,
+	reason     text not null default '',
+	created_at timestamptz not null default now()
+);
+
+create index if not exists rating_changes_chat_id_idx on rating_changes (chat_id, created_at);
+
+-- user_profiles holds per-user flags, unlike users which is per subscription.
+create table if not exists user_profiles (
+	user_id         bigint primary key,
+	age_verified    boolean not null default false,
+	age_verified_at timestamptz,
+	updated_at      timestamptz not null default now()
+);
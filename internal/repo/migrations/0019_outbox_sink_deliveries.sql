@@ -0,0 +1,8 @@
+-- outbox_sink_deliveries records sinks that accepted an event, so a retry
+-- after a partial failure runs only the sinks that failed.
+create table if not exists outbox_sink_deliveries (
+	event_id     bigint not null references outbox (id) on delete cascade,
+	sink         text not null,
+	delivered_at timestamptz not null default now(),
+	primary key (event_id, sink)
+);
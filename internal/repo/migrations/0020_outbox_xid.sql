@@ -0,0 +1,5 @@
+-- xid records the transaction that wrote an event. Readers of the event
+-- stream order by it to never pass events of transactions still in progress.
+alter table outbox add column if not exists xid xid8 not null default pg_current_xact_id();
+
+create index if not exists outbox_xid_idx on outbox (xid, id);
@@ -0,0 +1,8 @@
+-- Refunds of Telegram Stars payments are recorded as pending before the
+-- provider is called and applied once it accepted them.
+alter table refunds add column if not exists status text not null default 'completed'
+	check (status in ('pending', 'completed', 'failed'));
+
+alter table refunds add column if not exists error text;
+
+create unique index if not exists refunds_pending_payment_idx on refunds (payment_id) where status = 'pending';
@@ -0,0 +1,7 @@
+-- subscriber_count caches active subscriptions of the chat for the catalog,
+-- it is refreshed periodically instead of counted on every request.
+alter table chat add column if not exists subscriber_count integer not null default 0;
+
+update chat c set subscriber_count = (select count(*) from users u where u.chat_id = c.chat_id and u.is_active);
+
+create index if not exists chat_subscriber_count_idx on chat (subscriber_count desc, chat_id) where status = 'active';
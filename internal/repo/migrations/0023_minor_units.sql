@@ -0,0 +1,51 @@
+-- Amounts written before 0010 are whole rubles, later ones minor units.
+-- Databases where the conversion already ran have chat.price widened to
+-- bigint; elsewhere rows older than 0010 are converted to kopecks.
+do $$
+declare
+	cutoff timestamptz;
+begin
+	if (select data_type from information_schema.columns
+		where table_schema = current_schema() and table_name = 'chat' and column_name = 'price') <> 'integer' then
+		return;
+	end if;
+
+	select applied_at into cutoff from schema_migrations where version = '0010_currencies';
+
+	-- Chats added or repriced since then have a price change from that time.
+	update chat c set price = price * 100
+	where c.currency = 'RUB' and not exists (
+		select 1 from price_changes pc where pc.chat_id = c.chat_id and pc.effective_at >= cutoff
+	);
+
+	update users u set locked_price = locked_price * 100
+	where u.locked_price is not null and not exists (
+		select 1 from price_changes pc where pc.chat_id = u.chat_id and pc.grandfathered and pc.effective_at >= cutoff
+	);
+
+	update price_changes set old_price = old_price * 100, new_price = new_price * 100 where effective_at < cutoff;
+
+	update payments set list_price = list_price * 100, amount = amount * 100, refunded_amount = refunded_amount * 100
+	where created_at < cutoff and currency = 'RUB';
+
+	update refunds set amount = amount * 100 where created_at < cutoff and currency = 'RUB';
+
+	update promo_codes set discount_value = discount_value * 100 where discount_type = 'fixed' and created_at < cutoff;
+
+	update promo_redemptions set discount = discount * 100 where created_at < cutoff;
+
+	update plans set price = price * 100 where created_at < cutoff;
+end
+$$;
+
+-- Minor units overflow integer sooner, money columns are widened to bigint.
+alter table chat alter column price type bigint;
+alter table users alter column locked_price type bigint;
+alter table price_changes alter column old_price type bigint, alter column new_price type bigint;
+alter table payments alter column list_price type bigint, alter column amount type bigint,
+	alter column refunded_amount type bigint;
+alter table refunds alter column amount type bigint;
+alter table invoices alter column list_price type bigint, alter column amount type bigint;
+alter table promo_codes alter column discount_value type bigint;
+alter table promo_redemptions alter column discount type bigint;
+alter table plans alter column price type bigint;
@@ -0,0 +1,4 @@
+-- Deliveries of deactivated webhooks are cancelled instead of sent.
+update webhook_deliveries d set status = 'cancelled'
+from webhooks w
+where w.id = d.webhook_id and not w.is_active and d.status = 'pending';
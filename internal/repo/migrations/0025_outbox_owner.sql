@@ -0,0 +1,15 @@
+-- owner_id is the owner of the chat when the event was written, so streams of
+-- an owner keep to that owner's events after a chat changes hands.
+alter table outbox add column if not exists owner_id bigint;
+
+-- Events before an accepted transfer belong to the owner who gave the chat away.
+update outbox o set owner_id = coalesce((
+	select t.from_owner_id from chat_transfers t
+	where t.chat_id = o.chat_id and t.status = 'accepted' and t.resolved_at > o.created_at
+	order by t.resolved_at
+	limit 1
+), c.owner_id)
+from chat c
+where c.chat_id = o.chat_id and o.owner_id is null;
+
+create index if not exists outbox_owner_id_idx on outbox (owner_id, xid, id);
@@ -0,0 +1,75 @@
+package repo
+
+// addEventQuery records the owner of the chat at the time of the event, so
+// it stays with that owner after the chat is transferred.
+const addEventQuery = `
+	insert into outbox (event_type, chat_id, user_id, owner_id, payload)
+	values
+	($1, $2, nullif($3, 0), (select owner_id from chat where chat_id = $2), $4)
+	returning id
+`
+
+// claimEventsQuery leases a batch of pending events until $2 by moving their
+// next attempt, so other dispatchers skip them while sinks run. It returns
+// sinks that already accepted each event.
+const claimEventsQuery = `
+	update outbox o set next_attempt_at = $2
+	from (
+		select id from outbox
+		where delivered_at is null and next_attempt_at <= now()
+		order by id
+		limit $1
+		for update skip locked
+	) due
+	where o.id = due.id
+	returning o.id, o.event_type, o.chat_id, coalesce(o.user_id, 0), o.payload, o.created_at,
+		array(select s.sink from outbox_sink_deliveries s where s.event_id = o.id)
+`
+
+const markSinkDeliveredQuery = `
+	insert into outbox_sink_deliveries (event_id, sink)
+	select unnest($1::bigint[]), $2
+	on conflict do nothing
+`
+
+const markEventsDeliveredQuery = `
+	update outbox set delivered_at = now(), attempts = attempts + 1, last_error = null
+	where id = any($1)
+`
+
+// markEventsFailedQuery postpones events with exponential backoff capped at an hour.
+const markEventsFailedQuery = `
+	update outbox set
+		attempts = attempts + 1,
+		last_error = $2,
+		next_attempt_at = now() + least(interval '1 second' * power(2, attempts), interval '1 hour')
+	where id = any($1)
+`
+
+// expireSubscriptionsQuery skips paused chats, whose subscriptions are frozen.
+const expireSubscriptionsQuery = `
+	update users set is_active = false, locked_price = null
+	where is_active and expired_date <= $1
+		and chat_id not in (select chat_id from chat where status = 'paused')
+	returning chat_id, user_id, expired_date
+`
+
+// getEventsQuery reads events after cursor ($1, $2) written by transactions
+// older than the oldest one in progress. Any event committed later belongs to
+// a transaction at or above that horizon, so it sorts after the cursor.
+const getEventsQuery = `
+	select o.id, o.event_type, o.chat_id, coalesce(o.user_id, 0), o.payload, o.created_at, o.xid::text::bigint
+	from outbox o
+	where (o.xid, o.id) > ($1::bigint::text::xid8, $2)
+		and o.xid < pg_snapshot_xmin(pg_current_snapshot())
+		and ($3 = 0 or o.chat_id = $3)
+		and ($4 = 0 or o.owner_id = $4)
+		and (cardinality($5::text[]) = 0 or o.event_type = any($5))
+	order by o.xid, o.id
+	limit $6
+`
+
+// getEventCursorQuery returns position before every event not yet visible.
+const getEventCursorQuery = `
+	select pg_snapshot_xmin(pg_current_snapshot())::text::bigint
+`
@@ -0,0 +1,86 @@
+package repo
+
+// lockOwnerPayoutsQuery serializes payout requests of an owner so each of
+// them sees the balance left by the previous one.
+const lockOwnerPayoutsQuery = `
+	select pg_advisory_xact_lock(hashtextextended('payouts:' || $1::text, 0))
+`
+
+// getBalanceTotalsQuery sums payments earned by an owner net of refunds,
+// splitting them by whether they were made before $2 and so left the
+// holding period. Commission is rounded down per payment.
+const getBalanceTotalsQuery = `
+	with p as (
+		select p.currency,
+			sum(p.amount) as gross,
+			sum(p.refunded_amount) as refunded,
+			sum(p.amount - p.refunded_amount) filter (where p.created_at <= $2) as cleared,
+			sum((p.amount - p.refunded_amount)::bigint * p.commission_bps / 10000) filter (where p.created_at <= $2)::bigint as cleared_commission,
+			sum(p.amount - p.refunded_amount) filter (where p.created_at > $2) as held,
+			sum((p.amount - p.refunded_amount)::bigint * p.commission_bps / 10000) filter (where p.created_at > $2)::bigint as held_commission
+		from payments p
+		where p.owner_id = $1
+		group by p.currency
+	), o as (
+		select currency,
+			sum(amount) filter (where status = 'paid')::bigint as paid,
+			sum(amount) filter (where status in ('requested', 'processing'))::bigint as outstanding
+		from payouts
+		where owner_id = $1
+		group by currency
+	)
+	select coalesce(p.currency, o.currency),
+		coalesce(p.gross, 0), coalesce(p.refunded, 0),
+		coalesce(p.cleared, 0), coalesce(p.cleared_commission, 0),
+		coalesce(p.held, 0), coalesce(p.held_commission, 0),
+		coalesce(o.paid, 0), coalesce(o.outstanding, 0)
+	from p full join o on o.currency = p.currency
+	order by 1
+`
+
+const payoutColumns = `
+	id, owner_id, amount, currency, status, reference, created_at, updated_at
+`
+
+const addPayoutQuery = `
+	insert into payouts (owner_id, amount, currency)
+	values
+	($1, $2, $3)
+	returning` + payoutColumns
+
+const getPayoutsQuery = `
+	select` + payoutColumns + `
+	from payouts
+	where owner_id = $1
+	order by created_at desc, id desc
+`
+
+const getPayoutQuery = `
+	select` + payoutColumns + `
+	from payouts
+	where id = $1
+	for update
+`
+
+const updatePayoutQuery = `
+	update payouts set status = $2, reference = $3, updated_at = now()
+	where id = $1
+	returning updated_at
+`
+
+const getStatementQuery = `
+	select 'payment' as type, p.id, p.chat_id, p.user_id, p.amount::bigint as amount, p.currency,
+		(p.amount - p.refunded_amount)::bigint * p.commission_bps / 10000 as commission, '' as status,
+		p.created_at
+	from payments p
+	where p.owner_id = $1 and p.created_at >= $2 and p.created_at < $3
+	union all
+	select 'refund', r.id, r.chat_id, r.user_id, -r.amount::bigint, r.currency, 0, '', r.created_at
+	from refunds r join payments p on p.id = r.payment_id
+	where p.owner_id = $1 and r.status = 'completed' and r.created_at >= $2 and r.created_at < $3
+	union all
+	select 'payout', id, 0, 0, -amount, currency, 0, status, created_at
+	from payouts
+	where owner_id = $1 and created_at >= $2 and created_at < $3
+	order by created_at, type, id
+`
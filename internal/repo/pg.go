@@ -2,16 +2,20 @@ package repo
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
+	"net/url"
 	"project/internal/config"
 	"project/internal/model"
+	"strconv"
 	"sync"
 	"time"
 
 	"project/internal/logger"
 
 	"github.com/exaring/otelpgx"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -20,23 +24,91 @@ var (
 	poolOnce sync.Once
 )
 
+// buildDSN returns connection string for cfg. Credentials are escaped, so
+// passwords may contain any characters. An empty result makes pgx fall back
+// to the PG* environment variables.
+func buildDSN(cfg config.Repo) string {
+	if cfg.DSN != "" {
+		return cfg.DSN
+	}
+
+	if cfg.Host == "" {
+		return ""
+	}
+
+	u := url.URL{
+		Scheme: "postgres",
+		Host:   cfg.Host,
+		Path:   "/" + cfg.Database,
+	}
+
+	if cfg.Port != "" {
+		u.Host = net.JoinHostPort(cfg.Host, cfg.Port)
+	}
+
+	if cfg.User != "" {
+		u.User = url.UserPassword(cfg.User, cfg.Pass)
+	}
+
+	if cfg.SSLMode != "" {
+		u.RawQuery = url.Values{"sslmode": {cfg.SSLMode}}.Encode()
+	}
+
+	return u.String()
+}
+
+func newPoolConfig(cfg config.Repo) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(buildDSN(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse db config. %w", err)
+	}
+
+	if cfg.MaxConns > 0 {
+		poolConfig.MaxConns = cfg.MaxConns
+	}
+
+	if cfg.MinConns > 0 {
+		poolConfig.MinConns = cfg.MinConns
+	}
+
+	if cfg.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+
+	if cfg.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+
+	if cfg.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
+	}
+
+	if cfg.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+
+	if cfg.ApplicationName != "" {
+		poolConfig.ConnConfig.RuntimeParams["application_name"] = cfg.ApplicationName
+	}
+
+	poolConfig.ConnConfig.Tracer = otelpgx.NewTracer()
+
+	return poolConfig, nil
+}
+
 func dbSetup(ctx context.Context, cfg config.Repo) (*pgxpool.Pool, error) {
 	var err error
 
 	poolOnce.Do(func() {
-		dsn := fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=%s", cfg.User, cfg.Pass, net.JoinHostPort(cfg.Host, cfg.Port), cfg.Database, cfg.SSLMode)
-
 		var poolConfig *pgxpool.Config
 
-		poolConfig, err = pgxpool.ParseConfig(dsn)
+		poolConfig, err = newPoolConfig(cfg)
 		if err != nil {
 			logger.GetLogger().Debug().Err(err).Msg("failed to parse db config")
 
 			return
 		}
 
-		poolConfig.ConnConfig.Tracer = otelpgx.NewTracer()
-
 		pool, err = pgxpool.NewWithConfig(ctx, poolConfig)
 		if err != nil {
 			logger.GetLogger().Debug().Err(err).Msg("failed to connect to db")
@@ -53,7 +125,11 @@ func dbSetup(ctx context.Context, cfg config.Repo) (*pgxpool.Pool, error) {
 }
 
 type pg struct {
-	pool *pgxpool.Pool
+	pool         *pgxpool.Pool
+	replicas     *replicaSet
+	queryTimeout time.Duration
+	// tx is set on repos handed out by Atomic, all calls then run inside it.
+	tx pgx.Tx
 }
 
 func NewPgRepo(ctx context.Context, cfg config.Repo) (Repo, error) {
@@ -66,276 +142,419 @@ func NewPgRepo(ctx context.Context, cfg config.Repo) (Repo, error) {
 		return nil, fmt.Errorf("failed to ping. %w", err)
 	}
 
+	if cfg.AutoMigrate {
+		if err := migrate(ctx, pool); err != nil {
+			return nil, fmt.Errorf("failed to migrate. %w", err)
+		}
+	}
+
 	result := &pg{
-		pool: pool,
+		pool:         pool,
+		queryTimeout: cfg.QueryTimeout,
+	}
+
+	if len(cfg.Replicas) > 0 {
+		result.replicas, err = newReplicaSet(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup replicas. %w", err)
+		}
 	}
 
 	return result, nil
 }
 
 func (p *pg) Close() error {
+	if p.tx != nil {
+		return nil
+	}
+
+	p.replicas.close()
 	p.pool.Close()
 
 	return nil
 }
 
-func (p *pg) AddNewChat(ctx context.Context, chat_id int, owner_id int, name string, description string, price int) error {
-	tx, err := p.pool.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction. %w", err)
+func (p *pg) Ping(ctx context.Context) error {
+	if err := p.pool.Ping(ctx); err != nil {
+		return fmt.Errorf("failed to ping. %w", err)
 	}
 
-	if _, err := tx.Exec(ctx, addNewChatQuery, chat_id, owner_id, name, description, price); err != nil {
-		return fmt.Errorf("failed to execute query. %w", err)
-	}
+	return nil
+}
 
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit query. %w", err)
-	}
+func (p *pg) CheckMigrations(ctx context.Context) error {
+	return checkMigrations(ctx, p.pool)
+}
 
-	return nil
+// ClearDeletedChat drops members, subscriptions, trials and price history of
+// chat if it is deleted, and closes its transfers, webhooks and invites.
+// Chats in other statuses are left as is.
+func (p *pg) ClearDeletedChat(ctx context.Context, chat_id int) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		for _, q := range []string{
+			clearDeletedChatMembersQuery,
+			cancelDeletedChatTransfersQuery,
+			closeDeletedChatWebhooksQuery,
+			cancelInactiveWebhookDeliveriesQuery,
+			clearDeletedChatSubscriptionsQuery,
+			clearDeletedChatTrialsQuery,
+			clearDeletedChatPriceHistoryQuery,
+			expireDeletedChatInvitesQuery,
+		} {
+			if _, err := tx.Exec(ctx, q, chat_id); err != nil {
+				return fmt.Errorf("failed to execute query. %w", err)
+			}
+		}
+
+		return nil
+	})
 }
 
-func (p *pg) GetChatsInfoByOwnerId(ctx context.Context, owner_id int) ([]model.ChatInfo, error) {
-	tx, err := p.pool.Begin(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction. %w", err)
-	}
+func (p *pg) AddNewChat(ctx context.Context, chat model.AddNewChat) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, addNewChatQuery, chat.ChatId, chat.OwnerId, chat.Name, chat.Description, chat.Amount(), chat.TrialDays, chat.Currency)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
 
-	rows, err := tx.Query(ctx, getChatsInfoByOwnerIdQuery, owner_id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute query. %w", err)
-	}
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("chat %d already exists. %w", chat.ChatId, model.ErrConflict)
+		}
 
-	info := make([]model.ChatInfo, 0)
+		if _, err := tx.Exec(ctx, addOwnerMemberQuery, chat.ChatId, chat.OwnerId); err != nil {
+			return fmt.Errorf("failed to add owner member. %w", err)
+		}
 
-	for rows.Next() {
-		var (
-			chat_id     int
-			name        string
-			description string
-			price       int
-		)
+		if _, err := tx.Exec(ctx, addPriceChangeQuery, chat.ChatId, nil, chat.Amount(), chat.OwnerId, false); err != nil {
+			return fmt.Errorf("failed to add price change. %w", err)
+		}
+
+		return nil
+	})
+}
 
-		if err := rows.Scan(&chat_id, &name, &description, &price); err != nil {
-			return nil, fmt.Errorf("failed to scan rows. %w", err)
+func (p *pg) GetChatsInfoByOwnerId(ctx context.Context, owner_id int, include_archived bool) ([]model.ChatInfo, error) {
+	var info []model.ChatInfo
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, getChatsInfoByOwnerIdQuery, owner_id, include_archived)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
 		}
 
-		info = append(info, model.ChatInfo{
-			ChatId:      chat_id,
-			Name:        name,
-			Description: description,
-			Price:       price,
+		info, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.ChatInfo, error) {
+			return scanChat(row)
 		})
-	}
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
 
-	if err := tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction. %w", err)
-	}
+		chat_ids := make([]int, 0, len(info))
 
-	return info, nil
-}
+		for _, c := range info {
+			chat_ids = append(chat_ids, c.ChatId)
+		}
 
-func (p *pg) DisableChat(ctx context.Context, chat_id int) error {
-	tx, err := p.pool.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction. %w", err)
-	}
+		plans, err := getPlans(ctx, tx, chat_ids)
+		if err != nil {
+			return err
+		}
 
-	if _, err := tx.Exec(ctx, disableChatQuery, chat_id); err != nil {
-		return fmt.Errorf("failed to execute query. %w", err)
-	}
+		for i := range info {
+			info[i].Plans = plans[info[i].ChatId]
+
+			if info[i].Plans == nil {
+				info[i].Plans = make([]model.Plan, 0)
+			}
+		}
 
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction. %w", err)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	return info, nil
 }
 
 func (p *pg) ChangeDescription(ctx context.Context, chat_id int, description string) error {
-	tx, err := p.pool.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction. %w", err)
-	}
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, changeDescriptionQuery, description, chat_id); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
 
-	if _, err := tx.Exec(ctx, changeDescriptionQuery, description, chat_id); err != nil {
-		return fmt.Errorf("failed to execute query. %w", err)
-	}
+		return nil
+	})
+}
 
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction. %w", err)
-	}
+func (p *pg) ChangeTrial(ctx context.Context, chat_id int, trial_days int) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, changeTrialQuery, trial_days, chat_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
 
-	return nil
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("chat %d. %w", chat_id, model.ErrNotFound)
+		}
+
+		return nil
+	})
 }
 
-func (p *pg) ChangePrice(ctx context.Context, chat_id int, price int) error {
-	tx, err := p.pool.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction. %w", err)
-	}
+func (p *pg) GetChatTrialDays(ctx context.Context, chat_id int) (int, error) {
+	var trial_days int
 
-	if _, err := tx.Exec(ctx, changePriceQuery, price, chat_id); err != nil {
-		return fmt.Errorf("failed to execute query. %w", err)
-	}
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, getChatTrialDaysQuery, chat_id).Scan(&trial_days)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("chat %d. %w", chat_id, model.ErrNotFound)
+		}
 
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction. %w", err)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
 	}
 
-	return nil
+	return trial_days, nil
 }
 
 func (p *pg) GetAllSlaves(ctx context.Context, chat_id int) ([]int, error) {
-	tx, err := p.pool.Begin(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction. %w", err)
-	}
+	var slaves []int
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, getAllSlavesQuery, chat_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
 
-	rows, err := tx.Query(ctx, getAllSlavesQuery, chat_id)
+		slaves, err = pgx.CollectRows(rows, pgx.RowTo[int])
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query. %w", err)
+		return nil, err
 	}
 
-	slaves := make([]int, 0)
+	return slaves, nil
+}
 
-	for rows.Next() {
-		var id int
+func (p *pg) NewSubscribe(ctx context.Context, chat_id int, user_id int) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		expired_date := time.Now().AddDate(0, 1, 0)
 
-		if err := rows.Scan(&id); err != nil {
-			return nil, fmt.Errorf("failed to scan rows. %w", err)
+		if _, err := tx.Exec(ctx, newSubscribeQuery, chat_id, user_id, expired_date); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
 		}
 
-		slaves = append(slaves, id)
-	}
+		return addEvent(ctx, tx, model.EventSubscriptionCreated, chat_id, user_id, map[string]any{"expired_date": expired_date})
+	})
+}
 
-	if err := tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction. %w", err)
-	}
+// StartTrial records that user took the trial of chat and reports false if
+// the user already had one.
+func (p *pg) StartTrial(ctx context.Context, chat_id int, user_id int) (bool, error) {
+	var started bool
 
-	return slaves, nil
-}
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, startTrialQuery, chat_id, user_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
 
-func (p *pg) NewSubscribe(ctx context.Context, chat_id int, user_id int) error {
-	tx, err := p.pool.Begin(ctx)
+		started = tag.RowsAffected() > 0
+
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction %w", err)
+		return false, err
 	}
 
-	if _, err := tx.Exec(ctx, newSubscribeQuery, chat_id, user_id, time.Now().AddDate(0, 1, 0)); err != nil {
-		return fmt.Errorf("failed to execute query. %w", err)
-	}
+	return started, nil
+}
 
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction. %w", err)
-	}
+func (p *pg) NewTrialSubscribe(ctx context.Context, chat_id int, user_id int, expired_date time.Time) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, newTrialSubscribeQuery, chat_id, user_id, expired_date); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
 
-	return nil
+		return addEvent(ctx, tx, model.EventTrialStarted, chat_id, user_id, map[string]any{"expired_date": expired_date})
+	})
 }
 
 func (p *pg) GetAllSubsciptions(ctx context.Context, user_id int) ([]int, error) {
-	tx, err := p.pool.Begin(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction. %w", err)
-	}
+	var subs []int
 
-	rows, err := tx.Query(ctx, getAllSubscriptionsQuery, user_id)
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, getAllSubscriptionsQuery, user_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		subs, err = pgx.CollectRows(rows, pgx.RowTo[int])
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute query. %w", err)
+		return nil, err
 	}
 
-	subs := make([]int, 0)
+	return subs, nil
+}
+
+// Pay records payment and extends the subscription it was made for by the
+// plan duration, or by one month when no plan is given.
+func (p *pg) Pay(ctx context.Context, payment model.Payment) (model.Payment, error) {
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		months := new(int)
+		*months = 1
 
-	for rows.Next() {
-		var sub int
+		if payment.PlanId != 0 {
+			plan, err := getPlan(ctx, tx, payment.PlanId, payment.ChatId)
+			if err != nil {
+				return err
+			}
 
-		if err := rows.Scan(&sub); err != nil {
-			return nil, fmt.Errorf("failed to scan rows. %w", err)
+			months = &plan.DurationMonths
+
+			if plan.Lifetime {
+				months = nil
+			}
 		}
 
-		subs = append(subs, sub)
-	}
+		var period_start, expired_date time.Time
 
-	if err := tx.Commit(ctx); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction. %w", err)
-	}
+		if err := tx.QueryRow(ctx, payQuery, payment.ChatId, payment.UserId, payment.PlanId, months).Scan(&period_start, &expired_date); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("subscription. %w", model.ErrNotFound)
+			}
 
-	return subs, nil
-}
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
 
-func (p *pg) Pay(ctx context.Context, chat_id int, user_id int) error {
-	tx, err := p.pool.Begin(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to begin new transaction. %w", err)
-	}
+		err := tx.QueryRow(ctx, addPaymentQuery, payment.ChatId, payment.UserId, payment.ListPrice.Amount, payment.Amount.Amount,
+			payment.PromoCode, payment.PlanId, payment.Amount.Currency, payment.TelegramChargeId, months,
+			payment.CommissionBasisPoints, period_start, expired_date).
+			Scan(&payment.Id, &payment.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to add payment. %w", err)
+		}
 
-	if _, err := tx.Exec(ctx, payQuery, chat_id, user_id); err != nil {
-		return fmt.Errorf("failed to execute query. %w", err)
-	}
+		payment.Refunded = model.NewMoney(0, payment.Amount.Currency)
+
+		payload := map[string]any{
+			"payment_id":   payment.Id,
+			"plan_id":      payment.PlanId,
+			"amount":       payment.Amount.Amount,
+			"currency":     payment.Amount.Currency,
+			"expired_date": expired_date,
+		}
 
-	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction. %w", err)
+		return addEvent(ctx, tx, model.EventPaymentSucceeded, payment.ChatId, payment.UserId, payload)
+	})
+	if err != nil {
+		return model.Payment{}, err
 	}
 
-	return nil
+	return payment, nil
 }
 
 func (p *pg) IsSubscribeExists(ctx context.Context, chat_id int, user_id int) (bool, error) {
-	tx, err := p.pool.Begin(ctx)
-	if err != nil {
-		return false, fmt.Errorf("failed to begin transaction. %w", err)
-	}
+	var res bool
 
-	rows, err := tx.Query(ctx, isSubscribeExistsQuery, chat_id, user_id)
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, isSubscribeExistsQuery, chat_id, user_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		users, err := pgx.CollectRows(rows, pgx.RowTo[int])
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		res = len(users) > 0
+
+		return nil
+	})
 	if err != nil {
-		return false, fmt.Errorf("failed to execute transaction. %w", err)
+		return false, err
 	}
 
-	res := false
+	return res, nil
+}
 
-	for rows.Next() {
-		var user int
+func (p *pg) IsPaid(ctx context.Context, chat_id int, user_id int) (bool, error) {
+	var res bool
 
-		if err := rows.Scan(&user); err != nil {
-			return false, fmt.Errorf("failed to scan rows. %w", err)
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, isPaidQuery, chat_id, user_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
 		}
 
-		res = user == user_id
-	}
+		paid, err := pgx.CollectRows(rows, pgx.RowTo[bool])
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		res = len(paid) > 0 && paid[len(paid)-1]
 
-	if err := tx.Commit(ctx); err != nil {
-		return false, fmt.Errorf("failed to commit transaction. %w", err)
+		return nil
+	})
+	if err != nil {
+		return false, err
 	}
 
 	return res, nil
 }
 
-func (p *pg) IsPaid(ctx context.Context, chat_id int, user_id int) (bool, error) {
-	tx, err := p.pool.Begin(ctx)
-	if err != nil {
-		return false, fmt.Errorf("failed to begin transaction. %w", err)
-	}
+func (p *pg) GetAccess(ctx context.Context, chat_id int, user_id int) (model.Access, error) {
+	res := model.Access{Access: model.AccessNone}
 
-	rows, err := tx.Query(ctx, isPaidQuery, chat_id, user_id)
-	if err != nil {
-		return false, fmt.Errorf("failed to execute query. %w", err)
-	}
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		var (
+			is_active    bool
+			is_trial     bool
+			expired_date time.Time
+		)
 
-	var res bool
+		err := tx.QueryRow(ctx, getAccessQuery, chat_id, user_id).Scan(&is_active, &is_trial, &expired_date)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
 
-	for rows.Next() {
-		r := false
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
 
-		if err := rows.Scan(&r); err != nil {
-			return false, fmt.Errorf("failed to scan rows. %w", err)
+		if !is_active {
+			return nil
 		}
 
-		res = r
-	}
+		res.Access = model.AccessPaid
+		res.ExpiredDate = &expired_date
+
+		if is_trial {
+			res.Access = model.AccessTrial
+		}
 
-	if err := tx.Commit(ctx); err != nil {
-		return false, fmt.Errorf("failed to commit transaction. %w", err)
+		return nil
+	})
+	if err != nil {
+		return model.Access{}, err
 	}
 
 	return res, nil
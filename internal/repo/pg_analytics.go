@@ -0,0 +1,103 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"project/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetAnalytics computes subscriber and revenue analytics of an owner's chats
+// from payments and the outbox.
+func (p *pg) GetAnalytics(ctx context.Context, req model.AnalyticsRequest) (model.Analytics, error) {
+	res := model.Analytics{
+		OwnerId:     req.OwnerId,
+		ChatId:      req.ChatId,
+		From:        req.From,
+		To:          req.To,
+		Granularity: req.Granularity,
+	}
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		args := []any{req.OwnerId, req.ChatId, req.From, req.To, req.Granularity}
+
+		rows, err := tx.Query(ctx, getAnalyticsSeriesQuery, args...)
+		if err != nil {
+			return fmt.Errorf("failed to get series. %w", err)
+		}
+
+		res.Series, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.AnalyticsPoint, error) {
+			point := model.AnalyticsPoint{MRR: make([]model.Money, 0)}
+
+			err := row.Scan(&point.Start, &point.ActiveSubscribers, &point.New, &point.Churned)
+
+			return point, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan series. %w", err)
+		}
+
+		index := make(map[int64]int, len(res.Series))
+
+		for i, point := range res.Series {
+			index[point.Start.UnixMicro()] = i
+		}
+
+		rows, err = tx.Query(ctx, getAnalyticsMRRQuery, args...)
+		if err != nil {
+			return fmt.Errorf("failed to get mrr. %w", err)
+		}
+
+		var (
+			start time.Time
+			mrr   model.Money
+		)
+
+		_, err = pgx.ForEachRow(rows, []any{&start, &mrr.Currency, &mrr.Amount}, func() error {
+			if i, ok := index[start.UnixMicro()]; ok {
+				res.Series[i].MRR = append(res.Series[i].MRR, mrr)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan mrr. %w", err)
+		}
+
+		err = tx.QueryRow(ctx, getConversionQuery, args[:4]...).Scan(&res.Subscribed, &res.Converted)
+		if err != nil {
+			return fmt.Errorf("failed to get conversion. %w", err)
+		}
+
+		err = tx.QueryRow(ctx, getAvgLifetimeQuery, args[:4]...).Scan(&res.AvgLifetimeDays)
+		if err != nil {
+			return fmt.Errorf("failed to get average lifetime. %w", err)
+		}
+
+		rows, err = tx.Query(ctx, getRevenueByPlanQuery, args[:4]...)
+		if err != nil {
+			return fmt.Errorf("failed to get revenue by plan. %w", err)
+		}
+
+		res.RevenueByPlan, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.PlanRevenue, error) {
+			var r model.PlanRevenue
+
+			err := row.Scan(&r.PlanId, &r.Name, &r.Revenue.Currency, &r.Payments, &r.Revenue.Amount)
+
+			return r, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan revenue by plan. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Analytics{}, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,82 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"project/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (p *pg) GetCatalog(ctx context.Context, query model.CatalogQuery) ([]model.CatalogChat, error) {
+	var chats []model.CatalogChat
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		var after model.CatalogCursor
+
+		if query.After != nil {
+			after = *query.After
+		}
+
+		rows, err := tx.Query(ctx, getCatalogQuery, query.Query, query.Category, query.Tags, query.Currency, query.Sort,
+			query.After != nil, after.SortKey, after.ChatId, query.Limit, query.HiddenRatings)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		chats, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.CatalogChat, error) {
+			var c model.CatalogChat
+
+			err := row.Scan(&c.ChatId, &c.Name, &c.Description, &c.Price.Amount, &c.Price.Currency, &c.ContentRating,
+				&c.Category, &c.Tags, &c.Subscribers, &c.SortKey)
+
+			return c, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return chats, nil
+}
+
+func (p *pg) RefreshSubscriberCounts(ctx context.Context) (int, error) {
+	var n int
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, refreshSubscriberCountsQuery)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		n = int(tag.RowsAffected())
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+func (p *pg) ChangeCatalogInfo(ctx context.Context, chat_id int, category string, tags []string) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, changeCatalogInfoQuery, chat_id, category, tags)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("chat %d. %w", chat_id, model.ErrNotFound)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,69 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"project/internal/logger"
+	"project/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// exportRows runs query in a read-only transaction and calls fn once scan
+// holds the next row. Rows are read from the connection as fn consumes them,
+// so a large export is never held in memory. Exports outlive the per-query
+// deadline and statement timeout, they run until done or until ctx is
+// canceled by the client going away.
+func (p *pg) exportRows(ctx context.Context, query string, args []any, scan []any, fn func() error) error {
+	tx, err := p.begin(ctx, readOnly)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction. %w", err)
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.GetLogger().Err(err).Msg("failed to rollback transaction")
+		}
+	}()
+
+	if _, err := tx.Exec(ctx, "set local statement_timeout = 0"); err != nil {
+		return fmt.Errorf("failed to disable statement timeout. %w", err)
+	}
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to execute query. %w", err)
+	}
+
+	if _, err := pgx.ForEachRow(rows, scan, fn); err != nil {
+		return fmt.Errorf("failed to export rows. %w", err)
+	}
+
+	return nil
+}
+
+func (p *pg) ExportSubscribers(ctx context.Context, chat_id int, fn func(model.SubscriberExport) error) error {
+	var s model.SubscriberExport
+
+	scan := []any{&s.UserId, &s.Status, &s.ExpiredDate, &s.PlanId, &s.Payments, &s.Paid.Amount, &s.Paid.Currency, &s.LastPaidAt}
+
+	return p.exportRows(ctx, exportSubscribersQuery, []any{chat_id}, scan, func() error {
+		return fn(s)
+	})
+}
+
+func (p *pg) ExportPayments(ctx context.Context, req model.PaymentsExportRequest, fn func(model.Payment) error) error {
+	var pm model.Payment
+
+	scan := []any{&pm.Id, &pm.ChatId, &pm.UserId, &pm.PlanId, &pm.ListPrice.Amount, &pm.Amount.Amount, &pm.Refunded.Amount,
+		&pm.Amount.Currency, &pm.CommissionBasisPoints, &pm.PromoCode, &pm.TelegramChargeId, &pm.RefundedAt, &pm.CreatedAt}
+
+	return p.exportRows(ctx, exportPaymentsQuery, []any{req.OwnerId, req.From, req.To}, scan, func() error {
+		pm.ListPrice.Currency = pm.Amount.Currency
+		pm.Refunded.Currency = pm.Amount.Currency
+
+		return fn(pm)
+	})
+}
@@ -0,0 +1,109 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"project/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func scanInviteToken(row pgx.Row) (model.InviteToken, error) {
+	var t model.InviteToken
+
+	err := row.Scan(&t.Token, &t.ChatId, &t.UserId, &t.ExpiresAt, &t.RedeemedAt)
+
+	return t, err
+}
+
+// AddInviteToken stores token and revokes earlier unredeemed tokens of the
+// same subscription, so only the latest one can be used.
+func (p *pg) AddInviteToken(ctx context.Context, token model.InviteToken) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, revokeInviteTokensQuery, token.ChatId, token.UserId); err != nil {
+			return fmt.Errorf("failed to revoke tokens. %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, addInviteTokenQuery, token.Token, token.ChatId, token.UserId, token.ExpiresAt); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (p *pg) GetActiveInviteToken(ctx context.Context, chat_id int, user_id int) (model.InviteToken, error) {
+	var token model.InviteToken
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+
+		token, err = scanInviteToken(tx.QueryRow(ctx, getActiveInviteTokenQuery, chat_id, user_id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("invite token. %w", model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.InviteToken{}, err
+	}
+
+	return token, nil
+}
+
+// GetInviteToken returns token locking it until the end of the unit of work.
+func (p *pg) GetInviteToken(ctx context.Context, token string) (model.InviteToken, error) {
+	var res model.InviteToken
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+
+		res, err = scanInviteToken(tx.QueryRow(ctx, getInviteTokenQuery, token))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("invite token. %w", model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.InviteToken{}, err
+	}
+
+	return res, nil
+}
+
+func (p *pg) RedeemInviteToken(ctx context.Context, token string, user_id int) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, redeemInviteTokenQuery, token, user_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("invite token already redeemed. %w", model.ErrConflict)
+		}
+
+		return nil
+	})
+}
+
+func (p *pg) ReleaseInviteToken(ctx context.Context, token string, user_id int) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, releaseInviteTokenQuery, token, user_id); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+}
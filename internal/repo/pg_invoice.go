@@ -0,0 +1,118 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"project/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (p *pg) GetChat(ctx context.Context, chat_id int) (model.ChatInfo, error) {
+	var c model.ChatInfo
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+
+		c, err = scanChat(tx.QueryRow(ctx, getChatQuery, chat_id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("chat %d. %w", chat_id, model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.ChatInfo{}, err
+	}
+
+	return c, nil
+}
+
+func (p *pg) AddInvoice(ctx context.Context, invoice model.Invoice) (model.Invoice, error) {
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, addInvoiceQuery, invoice.ChatId, invoice.UserId, invoice.PlanId, invoice.PromoCode,
+			invoice.Payload, invoice.ListPrice.Amount, invoice.Amount.Amount, invoice.Amount.Currency).
+			Scan(&invoice.Id, &invoice.Status, &invoice.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Invoice{}, err
+	}
+
+	return invoice, nil
+}
+
+// GetInvoice returns invoice locking it until the end of the unit of work.
+func (p *pg) GetInvoice(ctx context.Context, payload string) (model.Invoice, error) {
+	var i model.Invoice
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, getInvoiceQuery, payload).
+			Scan(&i.Id, &i.ChatId, &i.UserId, &i.PlanId, &i.PromoCode, &i.Payload,
+				&i.ListPrice.Amount, &i.Amount.Amount, &i.Amount.Currency, &i.Status, &i.PaymentId, &i.CreatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("invoice. %w", model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		i.ListPrice.Currency = i.Amount.Currency
+
+		return nil
+	})
+	if err != nil {
+		return model.Invoice{}, err
+	}
+
+	return i, nil
+}
+
+func (p *pg) MarkInvoicePaid(ctx context.Context, id int64, payment_id int64) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, markInvoicePaidQuery, id, payment_id); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetPayment returns payment locking it until the end of the unit of work.
+func (p *pg) GetPayment(ctx context.Context, chat_id int, payment_id int64) (model.Payment, error) {
+	var pm model.Payment
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, getPaymentQuery, chat_id, payment_id).
+			Scan(&pm.Id, &pm.ChatId, &pm.UserId, &pm.PlanId, &pm.ListPrice.Amount, &pm.Amount.Amount, &pm.Refunded.Amount,
+				&pm.Amount.Currency, &pm.CommissionBasisPoints, &pm.PromoCode, &pm.TelegramChargeId, &pm.RefundedAt, &pm.CreatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("payment %d. %w", payment_id, model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		pm.ListPrice.Currency = pm.Amount.Currency
+		pm.Refunded.Currency = pm.Amount.Currency
+
+		return nil
+	})
+	if err != nil {
+		return model.Payment{}, err
+	}
+
+	return pm, nil
+}
@@ -0,0 +1,195 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"project/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func scanChat(row pgx.Row) (model.ChatInfo, error) {
+	var (
+		c     model.ChatInfo
+		price model.Money
+	)
+
+	err := row.Scan(&c.ChatId, &c.Name, &c.Description, &price.Amount, &price.Currency, &c.TrialDays,
+		&c.ContentRating, &c.Category, &c.Tags, &c.Status, &c.StatusChangedAt, &c.IsActive)
+
+	c.SetPrice(price)
+
+	return c, err
+}
+
+// LockChat returns chat and locks it until the end of the unit of work.
+func (p *pg) LockChat(ctx context.Context, chat_id int) (model.ChatInfo, error) {
+	return p.lockChat(ctx, lockChatQuery, chat_id)
+}
+
+func (p *pg) LockChatShared(ctx context.Context, chat_id int) (model.ChatInfo, error) {
+	return p.lockChat(ctx, lockChatSharedQuery, chat_id)
+}
+
+func (p *pg) lockChat(ctx context.Context, query string, chat_id int) (model.ChatInfo, error) {
+	var c model.ChatInfo
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+
+		c, err = scanChat(tx.QueryRow(ctx, query, chat_id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("chat %d. %w", chat_id, model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.ChatInfo{}, err
+	}
+
+	return c, nil
+}
+
+// SetChatStatus moves chat from old_status to status and publishes the
+// change.
+func (p *pg) SetChatStatus(ctx context.Context, chat_id int, old_status string, status string) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, setChatStatusQuery, chat_id, status)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("chat %d. %w", chat_id, model.ErrNotFound)
+		}
+
+		payload := map[string]any{
+			"old_status": old_status,
+			"status":     status,
+		}
+
+		if err := addEvent(ctx, tx, model.EventChatStatusChanged, chat_id, 0, payload); err != nil {
+			return err
+		}
+
+		if old_status != model.ChatActive {
+			return nil
+		}
+
+		return addEvent(ctx, tx, model.EventChatDisabled, chat_id, 0, payload)
+	})
+}
+
+// ExtendSubscriptions adds the time since paused_at to active subscriptions
+// of chat and the periods paid for them. It returns the number of extended
+// subscriptions.
+func (p *pg) ExtendSubscriptions(ctx context.Context, chat_id int, paused_at time.Time) (int, error) {
+	var count int
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, extendSubscriptionsQuery, chat_id, paused_at)
+		if err != nil {
+			return fmt.Errorf("failed to extend subscriptions. %w", err)
+		}
+
+		count = int(tag.RowsAffected())
+
+		if _, err := tx.Exec(ctx, extendPaymentPeriodsQuery, chat_id, paused_at); err != nil {
+			return fmt.Errorf("failed to extend payment periods. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (p *pg) CountPaidSubscribers(ctx context.Context, chat_id int) (int, error) {
+	var count int
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, countPaidSubscribersQuery, chat_id).Scan(&count); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// DeleteChat revokes remaining subscriptions of chat and closes its plans and
+// promo codes. Payments and refunds are kept for owner balances. It returns
+// the number of revoked subscriptions.
+func (p *pg) DeleteChat(ctx context.Context, chat_id int) (int, error) {
+	var count int
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, revokeSubscriptionsQuery, chat_id)
+		if err != nil {
+			return fmt.Errorf("failed to revoke subscriptions. %w", err)
+		}
+
+		type revoked struct {
+			user_id      int
+			expired_date time.Time
+		}
+
+		subs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (revoked, error) {
+			var r revoked
+
+			err := row.Scan(&r.user_id, &r.expired_date)
+
+			return r, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		for _, sub := range subs {
+			payload := map[string]any{
+				"reason":       "chat_deleted",
+				"expired_date": sub.expired_date,
+			}
+
+			if err := addEvent(ctx, tx, model.EventSubscriptionRevoked, chat_id, sub.user_id, payload); err != nil {
+				return err
+			}
+		}
+
+		count = len(subs)
+
+		if _, err := tx.Exec(ctx, closePaymentPeriodsQuery, chat_id); err != nil {
+			return fmt.Errorf("failed to close payment periods. %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, closePlansQuery, chat_id); err != nil {
+			return fmt.Errorf("failed to close plans. %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, closePromoCodesQuery, chat_id); err != nil {
+			return fmt.Errorf("failed to close promo codes. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
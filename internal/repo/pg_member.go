@@ -0,0 +1,233 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"project/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (p *pg) GetChatMemberRole(ctx context.Context, chat_id int, user_id int) (string, error) {
+	var role string
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, getChatMemberRoleQuery, chat_id, user_id).Scan(&role)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("member %d of chat %d. %w", user_id, chat_id, model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return role, nil
+}
+
+func (p *pg) AddChatMember(ctx context.Context, member model.ChatMember) (model.ChatMember, error) {
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, addChatMemberQuery, member.ChatId, member.UserId, member.Role, member.AddedBy).
+			Scan(&member.CreatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("user %d owns chat %d. %w", member.UserId, member.ChatId, model.ErrConflict)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.ChatMember{}, err
+	}
+
+	return member, nil
+}
+
+func (p *pg) GetChatMembers(ctx context.Context, chat_id int) ([]model.ChatMember, error) {
+	var members []model.ChatMember
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, getChatMembersQuery, chat_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		members, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.ChatMember, error) {
+			var m model.ChatMember
+
+			err := row.Scan(&m.ChatId, &m.UserId, &m.Role, &m.AddedBy, &m.CreatedAt)
+
+			return m, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (p *pg) RemoveChatMember(ctx context.Context, chat_id int, user_id int) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, removeChatMemberQuery, chat_id, user_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("member %d of chat %d. %w", user_id, chat_id, model.ErrNotFound)
+		}
+
+		return nil
+	})
+}
+
+func scanTransfer(row pgx.Row) (model.ChatTransfer, error) {
+	var t model.ChatTransfer
+
+	err := row.Scan(&t.Id, &t.ChatId, &t.FromOwnerId, &t.ToOwnerId, &t.Status, &t.CreatedAt, &t.ResolvedAt)
+
+	return t, err
+}
+
+// AddTransfer adds pending transfer of chat, cancelling the one it replaces.
+func (p *pg) AddTransfer(ctx context.Context, transfer model.ChatTransfer) (model.ChatTransfer, error) {
+	var res model.ChatTransfer
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, cancelPendingTransferQuery, transfer.ChatId); err != nil {
+			return fmt.Errorf("failed to cancel pending transfer. %w", err)
+		}
+
+		var err error
+
+		res, err = scanTransfer(tx.QueryRow(ctx, addTransferQuery, transfer.ChatId, transfer.FromOwnerId, transfer.ToOwnerId))
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.ChatTransfer{}, err
+	}
+
+	return res, nil
+}
+
+// GetPendingTransfer returns pending transfer of chat and locks it inside a
+// unit of work.
+func (p *pg) GetPendingTransfer(ctx context.Context, chat_id int) (model.ChatTransfer, error) {
+	var res model.ChatTransfer
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+
+		res, err = scanTransfer(tx.QueryRow(ctx, getPendingTransferQuery, chat_id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("pending transfer of chat %d. %w", chat_id, model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.ChatTransfer{}, err
+	}
+
+	return res, nil
+}
+
+func (p *pg) GetPendingTransfers(ctx context.Context, user_id int) ([]model.ChatTransfer, error) {
+	var transfers []model.ChatTransfer
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, getPendingTransfersQuery, user_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		transfers, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.ChatTransfer, error) {
+			return scanTransfer(row)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transfers, nil
+}
+
+// ResolveTransfer moves pending transfer to transfer.Status. An accepted
+// transfer makes its recipient the chat owner, removes the previous owner
+// from members and turns off webhooks they set up for the chat.
+func (p *pg) ResolveTransfer(ctx context.Context, transfer model.ChatTransfer) (model.ChatTransfer, error) {
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, resolveTransferQuery, transfer.Id, transfer.Status).Scan(&transfer.ResolvedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("pending transfer %d. %w", transfer.Id, model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		if transfer.Status != model.TransferAccepted {
+			return nil
+		}
+
+		if _, err := tx.Exec(ctx, setChatOwnerQuery, transfer.ChatId, transfer.ToOwnerId); err != nil {
+			return fmt.Errorf("failed to set chat owner. %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, removeOwnerMemberQuery, transfer.ChatId); err != nil {
+			return fmt.Errorf("failed to remove previous owner. %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, addOwnerMemberQuery, transfer.ChatId, transfer.ToOwnerId); err != nil {
+			return fmt.Errorf("failed to add owner. %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, deactivateOwnerWebhooksQuery, transfer.ChatId, transfer.FromOwnerId); err != nil {
+			return fmt.Errorf("failed to deactivate webhooks. %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, cancelInactiveWebhookDeliveriesQuery, transfer.ChatId); err != nil {
+			return fmt.Errorf("failed to cancel deliveries. %w", err)
+		}
+
+		return addEvent(ctx, tx, model.EventChatTransferred, transfer.ChatId, 0, map[string]any{
+			"transfer_id":   transfer.Id,
+			"from_owner_id": transfer.FromOwnerId,
+			"to_owner_id":   transfer.ToOwnerId,
+		})
+	})
+	if err != nil {
+		return model.ChatTransfer{}, err
+	}
+
+	return transfer, nil
+}
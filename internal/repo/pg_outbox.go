@@ -0,0 +1,190 @@
+package repo
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"project/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// addEvent writes event into the outbox as part of tx, so it is published
+// only if the state change it describes is committed. Webhook deliveries are
+// queued in the same tx.
+func addEvent(ctx context.Context, tx pgx.Tx, event_type string, chat_id int, user_id int, payload any) error {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event payload. %w", err)
+	}
+
+	var id int64
+
+	if err := tx.QueryRow(ctx, addEventQuery, event_type, chat_id, user_id, b).Scan(&id); err != nil {
+		return fmt.Errorf("failed to add %s event. %w", event_type, err)
+	}
+
+	if _, err := tx.Exec(ctx, enqueueWebhookDeliveriesQuery, id, chat_id, event_type); err != nil {
+		return fmt.Errorf("failed to enqueue webhook deliveries. %w", err)
+	}
+
+	return nil
+}
+
+func (p *pg) ClaimEvents(ctx context.Context, limit int, lease time.Duration) ([]model.OutboxEvent, error) {
+	var events []model.OutboxEvent
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, claimEventsQuery, limit, time.Now().Add(lease))
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		events, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.OutboxEvent, error) {
+			var e model.OutboxEvent
+
+			err := row.Scan(&e.Id, &e.Type, &e.ChatId, &e.UserId, &e.Payload, &e.CreatedAt, &e.DeliveredTo)
+
+			return e, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(events, func(a, b model.OutboxEvent) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+
+	return events, nil
+}
+
+func (p *pg) MarkSinkDelivered(ctx context.Context, sink string, ids []int64) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, markSinkDeliveredQuery, ids, sink); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (p *pg) MarkEventsDelivered(ctx context.Context, ids []int64) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, markEventsDeliveredQuery, ids); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (p *pg) MarkEventsFailed(ctx context.Context, ids []int64, reason string) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, markEventsFailedQuery, ids, reason); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (p *pg) ExpireSubscriptions(ctx context.Context, now time.Time) (int, error) {
+	var count int
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, expireSubscriptionsQuery, now)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		type expired struct {
+			chat_id      int
+			user_id      int
+			expired_date time.Time
+		}
+
+		subs, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (expired, error) {
+			var e expired
+
+			err := row.Scan(&e.chat_id, &e.user_id, &e.expired_date)
+
+			return e, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		for _, sub := range subs {
+			payload := map[string]any{"expired_date": sub.expired_date}
+
+			if err := addEvent(ctx, tx, model.EventSubscriptionExpired, sub.chat_id, sub.user_id, payload); err != nil {
+				return err
+			}
+		}
+
+		count = len(subs)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (p *pg) GetEvents(ctx context.Context, after model.EventCursor, filter model.EventFilter, limit int) ([]model.Event, error) {
+	var events []model.Event
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, getEventsQuery, after.Xid, after.Id, filter.ChatId, filter.OwnerId, filter.Types, limit)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		events, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Event, error) {
+			var e model.Event
+
+			err := row.Scan(&e.Id, &e.Type, &e.ChatId, &e.UserId, &e.Payload, &e.CreatedAt, &e.Xid)
+
+			return e, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (p *pg) GetEventCursor(ctx context.Context) (model.EventCursor, error) {
+	var cursor model.EventCursor
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, getEventCursorQuery).Scan(&cursor.Xid); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.EventCursor{}, err
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,186 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"project/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func scanPayout(row pgx.Row) (model.Payout, error) {
+	var p model.Payout
+
+	err := row.Scan(&p.Id, &p.OwnerId, &p.Amount.Amount, &p.Amount.Currency, &p.Status, &p.Reference, &p.CreatedAt, &p.UpdatedAt)
+
+	return p, err
+}
+
+// LockOwnerPayouts blocks other payout requests of the owner until the end of
+// the unit of work.
+func (p *pg) LockOwnerPayouts(ctx context.Context, owner_id int) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, lockOwnerPayoutsQuery, owner_id); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+}
+
+// GetBalanceTotals returns sums of owner's payments and payouts per currency.
+// Payments made after cleared_before are still on hold.
+func (p *pg) GetBalanceTotals(ctx context.Context, owner_id int, cleared_before time.Time) ([]model.BalanceTotals, error) {
+	var totals []model.BalanceTotals
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, getBalanceTotalsQuery, owner_id, cleared_before)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		totals, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.BalanceTotals, error) {
+			var t model.BalanceTotals
+
+			err := row.Scan(&t.Currency, &t.Gross, &t.Refunded, &t.Cleared, &t.ClearedCommission,
+				&t.Held, &t.HeldCommission, &t.PayoutsPaid, &t.PayoutsOutstanding)
+
+			return t, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return totals, nil
+}
+
+func (p *pg) AddPayout(ctx context.Context, payout model.Payout) (model.Payout, error) {
+	var res model.Payout
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+
+		res, err = scanPayout(tx.QueryRow(ctx, addPayoutQuery, payout.OwnerId, payout.Amount.Amount, payout.Amount.Currency))
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Payout{}, err
+	}
+
+	return res, nil
+}
+
+func (p *pg) GetPayouts(ctx context.Context, owner_id int) ([]model.Payout, error) {
+	var payouts []model.Payout
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, getPayoutsQuery, owner_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		payouts, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Payout, error) {
+			return scanPayout(row)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payouts, nil
+}
+
+// GetPayout returns payout locking it until the end of the unit of work.
+func (p *pg) GetPayout(ctx context.Context, id int64) (model.Payout, error) {
+	var payout model.Payout
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+
+		payout, err = scanPayout(tx.QueryRow(ctx, getPayoutQuery, id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("payout %d. %w", id, model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Payout{}, err
+	}
+
+	return payout, nil
+}
+
+func (p *pg) UpdatePayout(ctx context.Context, payout model.Payout) (model.Payout, error) {
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, updatePayoutQuery, payout.Id, payout.Status, payout.Reference).Scan(&payout.UpdatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("payout %d. %w", payout.Id, model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Payout{}, err
+	}
+
+	return payout, nil
+}
+
+func (p *pg) GetStatement(ctx context.Context, owner_id int, from time.Time, to time.Time) ([]model.StatementEntry, error) {
+	var entries []model.StatementEntry
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, getStatementQuery, owner_id, from, to)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		entries, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.StatementEntry, error) {
+			var e model.StatementEntry
+
+			err := row.Scan(&e.Type, &e.Id, &e.ChatId, &e.UserId, &e.Amount.Amount, &e.Amount.Currency,
+				&e.Commission.Amount, &e.Status, &e.CreatedAt)
+
+			e.Commission.Currency = e.Amount.Currency
+
+			return e, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,148 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"project/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func scanPlan(row pgx.Row) (model.Plan, error) {
+	var p model.Plan
+
+	err := row.Scan(&p.Id, &p.ChatId, &p.Name, &p.Price.Amount, &p.Price.Currency, &p.DurationMonths, &p.Lifetime)
+
+	return p, err
+}
+
+func getPlan(ctx context.Context, tx pgx.Tx, id int64, chat_id int) (model.Plan, error) {
+	plan, err := scanPlan(tx.QueryRow(ctx, getPlanQuery, id, chat_id))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.Plan{}, fmt.Errorf("plan %d. %w", id, model.ErrNotFound)
+	}
+
+	if err != nil {
+		return model.Plan{}, fmt.Errorf("failed to get plan. %w", err)
+	}
+
+	return plan, nil
+}
+
+// getPlans returns active plans of chats grouped by chat id.
+func getPlans(ctx context.Context, tx pgx.Tx, chat_ids []int) (map[int][]model.Plan, error) {
+	rows, err := tx.Query(ctx, getPlansQuery, chat_ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plans. %w", err)
+	}
+
+	plans, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Plan, error) {
+		return scanPlan(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan plans. %w", err)
+	}
+
+	res := make(map[int][]model.Plan, len(chat_ids))
+
+	for _, plan := range plans {
+		res[plan.ChatId] = append(res[plan.ChatId], plan)
+	}
+
+	return res, nil
+}
+
+func (p *pg) AddPlan(ctx context.Context, plan model.Plan) (int64, error) {
+	var id int64
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, addPlanQuery, plan.ChatId, plan.Name, plan.Price.Amount, plan.DurationMonths).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("chat %d. %w", plan.ChatId, model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (p *pg) GetPlans(ctx context.Context, chat_id int) ([]model.Plan, error) {
+	var plans []model.Plan
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		byChat, err := getPlans(ctx, tx, []int{chat_id})
+		if err != nil {
+			return err
+		}
+
+		plans = byChat[chat_id]
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if plans == nil {
+		plans = make([]model.Plan, 0)
+	}
+
+	return plans, nil
+}
+
+func (p *pg) GetPlan(ctx context.Context, id int64, chat_id int) (model.Plan, error) {
+	var plan model.Plan
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+
+		plan, err = getPlan(ctx, tx, id, chat_id)
+
+		return err
+	})
+	if err != nil {
+		return model.Plan{}, err
+	}
+
+	return plan, nil
+}
+
+func (p *pg) UpdatePlan(ctx context.Context, plan model.Plan) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, updatePlanQuery, plan.Id, plan.ChatId, plan.Name, plan.Price.Amount, plan.DurationMonths)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("plan %d. %w", plan.Id, model.ErrNotFound)
+		}
+
+		return nil
+	})
+}
+
+func (p *pg) DeletePlan(ctx context.Context, id int64, chat_id int) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, deletePlanQuery, id, chat_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("plan %d. %w", id, model.ErrNotFound)
+		}
+
+		return nil
+	})
+}
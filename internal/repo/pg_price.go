@@ -0,0 +1,131 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"project/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ChangePrice sets new chat price and records the change in price history.
+func (p *pg) ChangePrice(ctx context.Context, change model.ChangePrice) (model.PriceChange, error) {
+	res := model.PriceChange{
+		ChatId:        change.ChatId,
+		ActorId:       change.ActorId,
+		Grandfathered: change.Grandfather,
+	}
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		var (
+			old_price int64
+			currency  string
+		)
+
+		err := tx.QueryRow(ctx, lockChatPriceQuery, change.ChatId).Scan(&old_price, &currency)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("chat %d. %w", change.ChatId, model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to lock chat price. %w", err)
+		}
+
+		if change.Currency != "" && change.Currency != currency {
+			return fmt.Errorf("chat %d is priced in %s. %w", change.ChatId, currency, model.ErrInvalidArgument)
+		}
+
+		old := model.NewMoney(old_price, currency)
+		price := change.Amount(currency)
+
+		res.OldPrice = &old
+		res.NewPrice = model.NewMoney(price, currency)
+
+		if _, err := tx.Exec(ctx, changePriceQuery, price, change.ChatId); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		if change.Grandfather {
+			if _, err := tx.Exec(ctx, grandfatherPriceQuery, change.ChatId, old_price); err != nil {
+				return fmt.Errorf("failed to lock subscriber prices. %w", err)
+			}
+		}
+
+		err = tx.QueryRow(ctx, addPriceChangeQuery, change.ChatId, old_price, price, change.ActorId, change.Grandfather).
+			Scan(&res.Id, &res.EffectiveAt)
+		if err != nil {
+			return fmt.Errorf("failed to add price change. %w", err)
+		}
+
+		return addEvent(ctx, tx, model.EventPriceChanged, change.ChatId, 0, map[string]any{
+			"price":         price,
+			"old_price":     old_price,
+			"actor_id":      change.ActorId,
+			"currency":      currency,
+			"grandfathered": change.Grandfather,
+		})
+	})
+	if err != nil {
+		return model.PriceChange{}, err
+	}
+
+	return res, nil
+}
+
+func (p *pg) GetPriceHistory(ctx context.Context, chat_id int) ([]model.PriceChange, error) {
+	var history []model.PriceChange
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, getPriceHistoryQuery, chat_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		history, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.PriceChange, error) {
+			var (
+				c         model.PriceChange
+				old_price *int64
+			)
+
+			err := row.Scan(&c.Id, &c.ChatId, &old_price, &c.NewPrice.Amount, &c.NewPrice.Currency, &c.ActorId, &c.Grandfathered, &c.EffectiveAt)
+
+			if old_price != nil {
+				old := model.NewMoney(*old_price, c.NewPrice.Currency)
+				c.OldPrice = &old
+			}
+
+			return c, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// GetLockedPrice returns the grandfathered price of an active subscriber.
+// It reports false when the subscriber pays the current chat price.
+func (p *pg) GetLockedPrice(ctx context.Context, chat_id int, user_id int) (int, bool, error) {
+	var price int
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		return tx.QueryRow(ctx, getLockedPriceQuery, chat_id, user_id).Scan(&price)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to execute query. %w", err)
+	}
+
+	return price, true, nil
+}
@@ -0,0 +1,179 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"project/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func scanPromoCode(row pgx.Row) (model.PromoCode, error) {
+	var c model.PromoCode
+
+	err := row.Scan(&c.ChatId, &c.Code, &c.DiscountType, &c.DiscountValue, &c.ValidFrom, &c.ValidUntil,
+		&c.UsageLimit, &c.PerUserLimit, &c.FirstPaymentOnly, &c.UsedCount)
+
+	return c, err
+}
+
+func (p *pg) GetChatPrice(ctx context.Context, chat_id int) (model.Money, error) {
+	var price model.Money
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, getChatPriceQuery, chat_id).Scan(&price.Amount, &price.Currency)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("chat %d. %w", chat_id, model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Money{}, err
+	}
+
+	return price, nil
+}
+
+func (p *pg) CountPayments(ctx context.Context, chat_id int, user_id int) (int, error) {
+	var count int
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, countPaymentsQuery, chat_id, user_id).Scan(&count); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (p *pg) AddPromoCode(ctx context.Context, code model.PromoCode) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, addPromoCodeQuery, code.ChatId, code.Code, code.DiscountType, code.DiscountValue,
+			code.ValidFrom, code.ValidUntil, code.UsageLimit, code.PerUserLimit, code.FirstPaymentOnly)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("promo code %s already exists. %w", code.Code, model.ErrConflict)
+		}
+
+		return nil
+	})
+}
+
+func (p *pg) GetPromoCodes(ctx context.Context, chat_id int) ([]model.PromoCode, error) {
+	var codes []model.PromoCode
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, getPromoCodesQuery, chat_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		codes, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.PromoCode, error) {
+			return scanPromoCode(row)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (p *pg) GetPromoCode(ctx context.Context, chat_id int, code string) (model.PromoCode, error) {
+	return p.getPromoCode(ctx, readOnly, getPromoCodeQuery, chat_id, code)
+}
+
+// LockPromoCode returns code locking it until the end of the unit of work.
+func (p *pg) LockPromoCode(ctx context.Context, chat_id int, code string) (model.PromoCode, error) {
+	return p.getPromoCode(ctx, readWrite, lockPromoCodeQuery, chat_id, code)
+}
+
+func (p *pg) getPromoCode(ctx context.Context, opts pgx.TxOptions, query string, chat_id int, code string) (model.PromoCode, error) {
+	var res model.PromoCode
+
+	err := p.WithTx(ctx, opts, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+
+		res, err = scanPromoCode(tx.QueryRow(ctx, query, chat_id, code))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("promo code %s. %w", code, model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.PromoCode{}, err
+	}
+
+	return res, nil
+}
+
+func (p *pg) DeletePromoCode(ctx context.Context, chat_id int, code string) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, deletePromoCodeQuery, chat_id, code)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("promo code %s. %w", code, model.ErrNotFound)
+		}
+
+		return nil
+	})
+}
+
+func (p *pg) CountPromoRedemptions(ctx context.Context, chat_id int, code string, user_id int) (int, error) {
+	var count int
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, countPromoRedemptionsQuery, chat_id, code, user_id).Scan(&count); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (p *pg) RedeemPromoCode(ctx context.Context, chat_id int, code string, user_id int, payment_id int64, discount int64) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, addPromoRedemptionQuery, chat_id, code, user_id, payment_id, discount); err != nil {
+			return fmt.Errorf("failed to add redemption. %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, incPromoUsageQuery, chat_id, code); err != nil {
+			return fmt.Errorf("failed to update usage. %w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,106 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"project/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ChangeRating sets content rating of chat, recording the change in its
+// rating history.
+func (p *pg) ChangeRating(ctx context.Context, change model.RatingChange) (model.RatingChange, error) {
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, setContentRatingQuery, change.ChatId, change.NewRating)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("chat %d. %w", change.ChatId, model.ErrNotFound)
+		}
+
+		err = tx.QueryRow(ctx, addRatingChangeQuery, change.ChatId, change.OldRating, change.NewRating, change.ActorId,
+			change.Reason).Scan(&change.Id, &change.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to add rating change. %w", err)
+		}
+
+		return addEvent(ctx, tx, model.EventRatingChanged, change.ChatId, 0, map[string]any{
+			"old_rating": change.OldRating,
+			"new_rating": change.NewRating,
+			"actor_id":   change.ActorId,
+		})
+	})
+	if err != nil {
+		return model.RatingChange{}, err
+	}
+
+	return change, nil
+}
+
+func (p *pg) GetRatingHistory(ctx context.Context, chat_id int) ([]model.RatingChange, error) {
+	var history []model.RatingChange
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, getRatingHistoryQuery, chat_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		history, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.RatingChange, error) {
+			var c model.RatingChange
+
+			err := row.Scan(&c.Id, &c.ChatId, &c.OldRating, &c.NewRating, &c.ActorId, &c.Reason, &c.CreatedAt)
+
+			return c, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// IsAgeVerified reports whether user verified their age, users without a
+// profile have not.
+func (p *pg) IsAgeVerified(ctx context.Context, user_id int) (bool, error) {
+	var verified bool
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, isAgeVerifiedQuery, user_id).Scan(&verified)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return verified, nil
+}
+
+func (p *pg) SetAgeVerified(ctx context.Context, user_id int, verified bool) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, setAgeVerifiedQuery, user_id, verified); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+}
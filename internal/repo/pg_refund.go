@@ -0,0 +1,229 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"project/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// applyRefund adds refund to the refunded total of its payment and takes back
+// share of the period it bought, or revokes access if refund.AccessRevoked is
+// set or a lifetime payment is refunded in full. It sets the resulting expiry
+// and whether access was revoked on refund.
+func applyRefund(ctx context.Context, tx pgx.Tx, refund *model.Refund, share float64) error {
+	var (
+		months *int
+		full   bool
+	)
+
+	err := tx.QueryRow(ctx, refundPaymentQuery, refund.PaymentId, refund.Amount.Amount).Scan(&months, &full)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("refund exceeds payment %d. %w", refund.PaymentId, model.ErrConflict)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to update payment. %w", err)
+	}
+
+	revoke := refund.AccessRevoked || (months == nil && full)
+
+	var (
+		was_active   bool
+		is_active    bool
+		expired_date time.Time
+	)
+
+	err = tx.QueryRow(ctx, refundSubscriptionQuery, refund.ChatId, refund.UserId, months, share, revoke).
+		Scan(&was_active, &is_active, &expired_date)
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return fmt.Errorf("failed to update subscription. %w", err)
+	default:
+		refund.ExpiredDate = &expired_date
+
+		if _, err := tx.Exec(ctx, clampPaymentPeriodsQuery, refund.ChatId, refund.UserId, expired_date); err != nil {
+			return fmt.Errorf("failed to update payment periods. %w", err)
+		}
+	}
+
+	refund.AccessRevoked = was_active && !is_active
+
+	return nil
+}
+
+func addRefundEvents(ctx context.Context, tx pgx.Tx, refund model.Refund) error {
+	payload := map[string]any{
+		"payment_id":     refund.PaymentId,
+		"refund_id":      refund.Id,
+		"amount":         refund.Amount.Amount,
+		"currency":       refund.Amount.Currency,
+		"access_revoked": refund.AccessRevoked,
+		"expired_date":   refund.ExpiredDate,
+	}
+
+	if err := addEvent(ctx, tx, model.EventPaymentRefunded, refund.ChatId, refund.UserId, payload); err != nil {
+		return err
+	}
+
+	if !refund.AccessRevoked {
+		return nil
+	}
+
+	return addEvent(ctx, tx, model.EventSubscriptionRevoked, refund.ChatId, refund.UserId, map[string]any{
+		"refund_id": refund.Id,
+	})
+}
+
+// AddRefund records completed refund of a payment and applies it, see
+// applyRefund.
+func (p *pg) AddRefund(ctx context.Context, refund model.Refund, share float64) (model.Refund, error) {
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if err := applyRefund(ctx, tx, &refund, share); err != nil {
+			return err
+		}
+
+		refund.Status = model.RefundCompleted
+
+		err := tx.QueryRow(ctx, addRefundQuery, refund.PaymentId, refund.ChatId, refund.UserId, refund.Amount.Amount,
+			refund.Amount.Currency, refund.Reason, refund.ActorId, refund.AccessRevoked, refund.ExpiredDate, refund.Status).
+			Scan(&refund.Id, &refund.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to add refund. %w", err)
+		}
+
+		return addRefundEvents(ctx, tx, refund)
+	})
+	if err != nil {
+		return model.Refund{}, err
+	}
+
+	return refund, nil
+}
+
+// AddPendingRefund records refund that waits for the payment provider without
+// applying it. A payment has at most one pending refund.
+func (p *pg) AddPendingRefund(ctx context.Context, refund model.Refund) (model.Refund, error) {
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		refund.Status = model.RefundPending
+
+		err := tx.QueryRow(ctx, addPendingRefundQuery, refund.PaymentId, refund.ChatId, refund.UserId, refund.Amount.Amount,
+			refund.Amount.Currency, refund.Reason, refund.ActorId, refund.AccessRevoked).
+			Scan(&refund.Id, &refund.CreatedAt)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("payment %d has a pending refund. %w", refund.PaymentId, model.ErrConflict)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to add refund. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Refund{}, err
+	}
+
+	return refund, nil
+}
+
+// CompleteRefund applies pending refund once the provider returned the money.
+func (p *pg) CompleteRefund(ctx context.Context, refund model.Refund, share float64) (model.Refund, error) {
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if err := applyRefund(ctx, tx, &refund, share); err != nil {
+			return err
+		}
+
+		tag, err := tx.Exec(ctx, completeRefundQuery, refund.Id, refund.AccessRevoked, refund.ExpiredDate)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("pending refund %d. %w", refund.Id, model.ErrNotFound)
+		}
+
+		refund.Status = model.RefundCompleted
+
+		return addRefundEvents(ctx, tx, refund)
+	})
+	if err != nil {
+		return model.Refund{}, err
+	}
+
+	return refund, nil
+}
+
+func (p *pg) FailRefund(ctx context.Context, refund_id int64, reason string) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, failRefundQuery, refund_id, reason); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+}
+
+func scanRefund(row pgx.Row) (model.Refund, error) {
+	var r model.Refund
+
+	err := row.Scan(&r.Id, &r.PaymentId, &r.ChatId, &r.UserId, &r.Amount.Amount, &r.Amount.Currency, &r.Reason,
+		&r.ActorId, &r.AccessRevoked, &r.ExpiredDate, &r.Status, &r.Error, &r.CreatedAt)
+
+	return r, err
+}
+
+func (p *pg) GetRefund(ctx context.Context, refund_id int64) (model.Refund, error) {
+	var refund model.Refund
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		var err error
+
+		refund, err = scanRefund(tx.QueryRow(ctx, getRefundQuery, refund_id))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("refund %d. %w", refund_id, model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Refund{}, err
+	}
+
+	return refund, nil
+}
+
+func (p *pg) GetRefunds(ctx context.Context, chat_id int) ([]model.Refund, error) {
+	var refunds []model.Refund
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, getRefundsQuery, chat_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		refunds, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Refund, error) {
+			return scanRefund(row)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return refunds, nil
+}
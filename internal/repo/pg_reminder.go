@@ -0,0 +1,70 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"project/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (p *pg) GetExpiringSubscriptions(ctx context.Context, now time.Time, from time.Duration, to time.Duration, limit int) ([]model.ExpiryReminder, error) {
+	var reminders []model.ExpiryReminder
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, getExpiringSubscriptionsQuery, now, from, to, int(to.Seconds()), limit)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		reminders, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.ExpiryReminder, error) {
+			r := model.ExpiryReminder{Window: to}
+
+			err := row.Scan(&r.ChatId, &r.UserId, &r.ChatName, &r.ExpiredDate)
+
+			return r, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reminders, nil
+}
+
+func (p *pg) AddReminder(ctx context.Context, reminder model.ExpiryReminder) (bool, error) {
+	var added bool
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, addReminderQuery, reminder.ChatId, reminder.UserId, int(reminder.Window.Seconds()), reminder.ExpiredDate)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		added = tag.RowsAffected() > 0
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return added, nil
+}
+
+func (p *pg) DeleteReminder(ctx context.Context, reminder model.ExpiryReminder) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, deleteReminderQuery, reminder.ChatId, reminder.UserId, int(reminder.Window.Seconds()), reminder.ExpiredDate); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+}
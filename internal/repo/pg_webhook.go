@@ -0,0 +1,219 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"project/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (p *pg) SetOwnerSecret(ctx context.Context, owner_id int, secret string, rotate bool) (string, error) {
+	var res string
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, setOwnerSecretQuery, owner_id, secret, rotate).Scan(&res); err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return res, nil
+}
+
+func (p *pg) AddWebhook(ctx context.Context, webhook model.AddWebhook) (int64, error) {
+	var id int64
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, addWebhookQuery, webhook.ChatId, webhook.OwnerId, webhook.Url, webhook.Events).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("chat %d of owner %d. %w", webhook.ChatId, webhook.OwnerId, model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (p *pg) GetWebhooks(ctx context.Context, chat_id int) ([]model.Webhook, error) {
+	var webhooks []model.Webhook
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, getWebhooksQuery, chat_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		webhooks, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Webhook, error) {
+			var w model.Webhook
+
+			err := row.Scan(&w.Id, &w.ChatId, &w.OwnerId, &w.Url, &w.Events, &w.CreatedAt)
+
+			return w, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (p *pg) DeleteWebhook(ctx context.Context, id int64, owner_id int) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		var chat_id int
+
+		err := tx.QueryRow(ctx, deleteWebhookQuery, id, owner_id).Scan(&chat_id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("webhook %d. %w", id, model.ErrNotFound)
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, cancelInactiveWebhookDeliveriesQuery, chat_id); err != nil {
+			return fmt.Errorf("failed to cancel deliveries. %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (p *pg) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.PendingWebhookDelivery, error) {
+	var deliveries []model.PendingWebhookDelivery
+
+	err := p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, claimWebhookDeliveriesQuery, limit, time.Now().Add(lease))
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		deliveries, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.PendingWebhookDelivery, error) {
+			var d model.PendingWebhookDelivery
+
+			err := row.Scan(&d.Id, &d.Attempts, &d.Url, &d.Secret,
+				&d.Event.Id, &d.Event.Type, &d.Event.ChatId, &d.Event.UserId, &d.Event.Payload, &d.Event.CreatedAt)
+
+			return d, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (p *pg) RecordWebhookAttempt(ctx context.Context, delivery_id int64, attempt model.WebhookAttempt, status string, next_attempt_at time.Time) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, addWebhookAttemptQuery, delivery_id, attempt.AttemptedAt, attempt.ResponseStatus, attempt.Error, attempt.DurationMs)
+		if err != nil {
+			return fmt.Errorf("failed to add attempt. %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, updateWebhookDeliveryQuery, delivery_id, status, next_attempt_at); err != nil {
+			return fmt.Errorf("failed to update delivery. %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (p *pg) GetWebhookDeliveries(ctx context.Context, webhook_id int64, owner_id int, limit int) ([]model.WebhookDelivery, error) {
+	var deliveries []model.WebhookDelivery
+
+	err := p.WithTx(ctx, readOnly, func(ctx context.Context, tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, getWebhookDeliveriesQuery, webhook_id, owner_id, limit)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		deliveries, err = pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.WebhookDelivery, error) {
+			var d model.WebhookDelivery
+
+			err := row.Scan(&d.Id, &d.WebhookId, &d.EventId, &d.EventType, &d.Status, &d.Attempts, &d.NextAttemptAt, &d.CreatedAt, &d.DeliveredAt)
+
+			return d, err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		ids := make([]int64, 0, len(deliveries))
+		byId := make(map[int64]*model.WebhookDelivery, len(deliveries))
+
+		for i := range deliveries {
+			deliveries[i].Log = make([]model.WebhookAttempt, 0)
+			ids = append(ids, deliveries[i].Id)
+			byId[deliveries[i].Id] = &deliveries[i]
+		}
+
+		rows, err = tx.Query(ctx, getWebhookAttemptsQuery, ids)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		var (
+			delivery_id int64
+			a           model.WebhookAttempt
+		)
+
+		_, err = pgx.ForEachRow(rows, []any{&delivery_id, &a.AttemptedAt, &a.ResponseStatus, &a.Error, &a.DurationMs}, func() error {
+			d := byId[delivery_id]
+			d.Log = append(d.Log, a)
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to scan rows. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (p *pg) Redeliver(ctx context.Context, delivery_id int64, owner_id int) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, redeliverQuery, delivery_id, owner_id)
+		if err != nil {
+			return fmt.Errorf("failed to execute query. %w", err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			return fmt.Errorf("delivery %d. %w", delivery_id, model.ErrNotFound)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,33 @@
+package repo
+
+const addPlanQuery = `
+	insert into plans (chat_id, name, price, duration_months)
+	select chat_id, $2, $3, nullif($4, 0) from chat where chat_id = $1
+	returning id
+`
+
+const planColumns = `
+	p.id, p.chat_id, p.name, p.price, c.currency, coalesce(p.duration_months, 0), p.duration_months is null
+`
+
+const getPlansQuery = `
+	select` + planColumns + `
+	from plans p join chat c on c.chat_id = p.chat_id
+	where p.chat_id = any($1) and p.is_active
+	order by p.chat_id, p.price
+`
+
+const getPlanQuery = `
+	select` + planColumns + `
+	from plans p join chat c on c.chat_id = p.chat_id
+	where p.id = $1 and p.chat_id = $2 and p.is_active
+`
+
+const updatePlanQuery = `
+	update plans set name = $3, price = $4, duration_months = nullif($5, 0)
+	where id = $1 and chat_id = $2 and is_active
+`
+
+const deletePlanQuery = `
+	update plans set is_active = false where id = $1 and chat_id = $2 and is_active
+`
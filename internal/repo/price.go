@@ -0,0 +1,31 @@
+package repo
+
+const lockChatPriceQuery = `
+	select price, currency from chat where chat_id = $1 for update
+`
+
+const addPriceChangeQuery = `
+	insert into price_changes (chat_id, old_price, new_price, actor_id, grandfathered)
+	values
+	($1, $2, $3, nullif($4, 0), $5)
+	returning id, effective_at
+`
+
+// grandfatherPriceQuery locks active paid subscribers to the old price unless
+// they are already locked to an earlier one.
+const grandfatherPriceQuery = `
+	update users set locked_price = $2
+	where chat_id = $1 and is_active and not is_trial and locked_price is null
+`
+
+const getPriceHistoryQuery = `
+	select h.id, h.chat_id, h.old_price, h.new_price, c.currency, coalesce(h.actor_id, 0), h.grandfathered, h.effective_at
+	from price_changes h join chat c on c.chat_id = h.chat_id
+	where h.chat_id = $1
+	order by h.effective_at desc, h.id desc
+`
+
+const getLockedPriceQuery = `
+	select locked_price from users
+	where chat_id = $1 and user_id = $2 and is_active and locked_price is not null
+`
@@ -0,0 +1,84 @@
+package repo
+
+const getChatPriceQuery = `
+	select price, currency from chat where chat_id = $1
+`
+
+const addPaymentQuery = `
+	insert into payments (chat_id, user_id, list_price, amount, promo_code, plan_id, currency, telegram_payment_charge_id,
+		duration_months, commission_bps, period_start, period_end, owner_id)
+	values
+	($1, $2, $3, $4, nullif($5, ''), nullif($6, 0), $7, nullif($8, ''), $9, $10, $11, $12,
+		(select owner_id from chat where chat_id = $1))
+	returning id, created_at
+`
+
+const countPaymentsQuery = `
+	select count(*) from payments where chat_id = $1 and user_id = $2
+`
+
+// addPromoCodeQuery revives a deleted code with the same name as a new one,
+// its past redemptions no longer count. It adds no row if the code is active.
+const addPromoCodeQuery = `
+	insert into promo_codes (chat_id, code, discount_type, discount_value, valid_from, valid_until,
+		usage_limit, per_user_limit, first_payment_only)
+	values
+	($1, $2, $3, $4, $5, $6, nullif($7, 0), nullif($8, 0), $9)
+	on conflict (chat_id, code) do update set
+		discount_type = excluded.discount_type,
+		discount_value = excluded.discount_value,
+		valid_from = excluded.valid_from,
+		valid_until = excluded.valid_until,
+		usage_limit = excluded.usage_limit,
+		per_user_limit = excluded.per_user_limit,
+		first_payment_only = excluded.first_payment_only,
+		used_count = 0,
+		is_active = true,
+		created_at = now()
+	where not promo_codes.is_active
+`
+
+const promoCodeColumns = `
+	chat_id, code, discount_type, discount_value, valid_from, valid_until,
+	coalesce(usage_limit, 0), coalesce(per_user_limit, 0), first_payment_only, used_count
+`
+
+const getPromoCodesQuery = `
+	select` + promoCodeColumns + `
+	from promo_codes
+	where chat_id = $1 and is_active
+	order by created_at
+`
+
+const getPromoCodeQuery = `
+	select` + promoCodeColumns + `
+	from promo_codes
+	where chat_id = $1 and code = $2 and is_active
+`
+
+// lockPromoCodeQuery locks the code so concurrent payments respect its limits.
+const lockPromoCodeQuery = getPromoCodeQuery + `
+	for update
+`
+
+const deletePromoCodeQuery = `
+	update promo_codes set is_active = false where chat_id = $1 and code = $2 and is_active
+`
+
+// countPromoRedemptionsQuery counts redemptions since the code was added,
+// those of a deleted code with the same name are left out.
+const countPromoRedemptionsQuery = `
+	select count(*)
+	from promo_redemptions r join promo_codes c on c.chat_id = r.chat_id and c.code = r.code
+	where r.chat_id = $1 and r.code = $2 and r.user_id = $3 and r.created_at >= c.created_at
+`
+
+const addPromoRedemptionQuery = `
+	insert into promo_redemptions (chat_id, code, user_id, payment_id, discount)
+	values
+	($1, $2, $3, $4, $5)
+`
+
+const incPromoUsageQuery = `
+	update promo_codes set used_count = used_count + 1 where chat_id = $1 and code = $2
+`
@@ -0,0 +1,33 @@
+package repo
+
+const setContentRatingQuery = `
+	update chat set content_rating = $2 where chat_id = $1
+`
+
+const addRatingChangeQuery = `
+	insert into rating_changes (chat_id, old_rating, new_rating, actor_id, reason)
+	values
+	($1, $2, $3, $4, $5)
+	returning id, created_at
+`
+
+const getRatingHistoryQuery = `
+	select id, chat_id, old_rating, new_rating, actor_id, reason, created_at
+	from rating_changes
+	where chat_id = $1
+	order by created_at desc, id desc
+`
+
+const isAgeVerifiedQuery = `
+	select age_verified from user_profiles where user_id = $1
+`
+
+const setAgeVerifiedQuery = `
+	insert into user_profiles (user_id, age_verified, age_verified_at)
+	values
+	($1, $2, case when $2 then now() end)
+	on conflict (user_id) do update set
+		age_verified = excluded.age_verified,
+		age_verified_at = excluded.age_verified_at,
+		updated_at = now()
+`
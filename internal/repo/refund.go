@@ -0,0 +1,81 @@
+package repo
+
+// refundPaymentQuery adds amount to refunded total unless it would exceed the
+// payment. It returns the duration the payment bought and whether it is now
+// refunded in full.
+const refundPaymentQuery = `
+	update payments set
+		refunded_amount = refunded_amount + $2,
+		refunded_at = case when refunded_amount + $2 >= amount then now() end
+	where id = $1 and refunded_amount + $2 <= amount
+	returning duration_months, refunded_at is not null
+`
+
+// refundSubscriptionQuery shortens the subscription by $4 share of $3 months,
+// or ends it now when $5 is set. Access already over is left as is.
+const refundSubscriptionQuery = `
+	update users u set
+		is_active = u.is_active and not $5 and u.expired_date - r.cut > now(),
+		expired_date = case
+			when $5 then least(u.expired_date, now())
+			else greatest(u.expired_date - r.cut, least(u.expired_date, now()))
+		end
+	from (
+		select
+			coalesce(make_interval(months => $3::integer) * $4::float8, interval '0') as cut,
+			(select is_active from users where chat_id = $1 and user_id = $2 for update) as was_active
+	) r
+	where u.chat_id = $1 and u.user_id = $2
+	returning r.was_active, u.is_active, u.expired_date
+`
+
+// clampPaymentPeriodsQuery ends paid periods of a subscription no later than
+// its expiry after a refund.
+const clampPaymentPeriodsQuery = `
+	update payments set
+		period_start = least(period_start, $3),
+		period_end = least(period_end, $3)
+	where chat_id = $1 and user_id = $2 and period_end > $3
+`
+
+const addRefundQuery = `
+	insert into refunds (payment_id, chat_id, user_id, amount, currency, reason, actor_id, access_revoked, expired_date, status)
+	values
+	($1, $2, $3, $4, $5, $6, nullif($7, 0), $8, $9, $10)
+	returning id, created_at
+`
+
+// addPendingRefundQuery returns no rows if the payment already has a pending
+// refund.
+const addPendingRefundQuery = `
+	insert into refunds (payment_id, chat_id, user_id, amount, currency, reason, actor_id, access_revoked, status)
+	values
+	($1, $2, $3, $4, $5, $6, nullif($7, 0), $8, 'pending')
+	on conflict (payment_id) where status = 'pending' do nothing
+	returning id, created_at
+`
+
+const completeRefundQuery = `
+	update refunds set status = 'completed', access_revoked = $2, expired_date = $3
+	where id = $1 and status = 'pending'
+`
+
+const failRefundQuery = `
+	update refunds set status = 'failed', error = $2
+	where id = $1 and status = 'pending'
+`
+
+const getRefundQuery = `
+	select id, payment_id, chat_id, user_id, amount, currency, reason, coalesce(actor_id, 0), access_revoked,
+		expired_date, status, coalesce(error, ''), created_at
+	from refunds
+	where id = $1
+`
+
+const getRefundsQuery = `
+	select id, payment_id, chat_id, user_id, amount, currency, reason, coalesce(actor_id, 0), access_revoked,
+		expired_date, status, coalesce(error, ''), created_at
+	from refunds
+	where chat_id = $1
+	order by created_at desc, id desc
+`
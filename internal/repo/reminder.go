@@ -0,0 +1,33 @@
+package repo
+
+// getExpiringSubscriptionsQuery returns paid subscriptions expiring in
+// ($2, $3] that have no reminder for window $4 and their current expiry yet.
+// Subscriptions of paused chats are frozen and not reminded about.
+const getExpiringSubscriptionsQuery = `
+	select u.chat_id, u.user_id, c.name, u.expired_date
+	from users u
+	join chat c on c.chat_id = u.chat_id
+	where u.is_active
+		and c.status <> 'paused'
+		and u.expired_date > $1 + $2::interval
+		and u.expired_date <= $1 + $3::interval
+		and not exists (
+			select 1 from expiry_reminders r
+			where r.chat_id = u.chat_id and r.user_id = u.user_id
+				and r.window_seconds = $4 and r.expired_date = u.expired_date
+		)
+	order by u.expired_date
+	limit $5
+`
+
+const addReminderQuery = `
+	insert into expiry_reminders (chat_id, user_id, window_seconds, expired_date)
+	values
+	($1, $2, $3, $4)
+	on conflict do nothing
+`
+
+const deleteReminderQuery = `
+	delete from expiry_reminders
+	where chat_id = $1 and user_id = $2 and window_seconds = $3 and expired_date = $4
+`
@@ -0,0 +1,170 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"project/internal/config"
+	"project/internal/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const defaultReplicaCheckPeriod = 5 * time.Second
+
+type replica struct {
+	name    string
+	pool    *pgxpool.Pool
+	healthy atomic.Bool
+}
+
+// replicaSet balances read-only transactions between healthy replicas.
+type replicaSet struct {
+	replicas []*replica
+	next     atomic.Uint64
+	period   time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func newReplicaSet(ctx context.Context, cfg config.Repo) (*replicaSet, error) {
+	rs := &replicaSet{
+		period: cfg.ReplicaCheckPeriod,
+		done:   make(chan struct{}),
+	}
+
+	if rs.period <= 0 {
+		rs.period = defaultReplicaCheckPeriod
+	}
+
+	for _, rc := range cfg.Replicas {
+		replicaCfg := cfg
+		replicaCfg.DSN = rc.DSN
+		replicaCfg.Host = rc.Host
+		replicaCfg.Port = rc.Port
+
+		poolConfig, err := newPoolConfig(replicaCfg)
+		if err != nil {
+			rs.closePools()
+
+			return nil, fmt.Errorf("failed to parse replica config. %w", err)
+		}
+
+		pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+		if err != nil {
+			rs.closePools()
+
+			return nil, fmt.Errorf("failed to create replica pool. %w", err)
+		}
+
+		r := &replica{
+			name: poolConfig.ConnConfig.Host,
+			pool: pool,
+		}
+
+		r.healthy.Store(pool.Ping(ctx) == nil)
+
+		rs.replicas = append(rs.replicas, r)
+	}
+
+	checkCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	rs.cancel = cancel
+
+	go rs.run(checkCtx)
+
+	return rs, nil
+}
+
+// pick returns next healthy replica or nil if there is none.
+func (rs *replicaSet) pick() *replica {
+	if rs == nil || len(rs.replicas) == 0 {
+		return nil
+	}
+
+	start := rs.next.Add(1)
+
+	for i := range rs.replicas {
+		r := rs.replicas[(int(start)+i)%len(rs.replicas)]
+
+		if r.healthy.Load() {
+			return r
+		}
+	}
+
+	return nil
+}
+
+func (rs *replicaSet) run(ctx context.Context) {
+	defer close(rs.done)
+
+	t := time.NewTicker(rs.period)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		for _, r := range rs.replicas {
+			pingCtx, cancel := context.WithTimeout(ctx, rs.period)
+			err := r.pool.Ping(pingCtx)
+			cancel()
+
+			if healthy := err == nil; r.healthy.Swap(healthy) != healthy {
+				logger.GetLogger().Info().Err(err).Str("replica", r.name).Bool("healthy", healthy).Msg("replica health changed")
+			}
+		}
+	}
+}
+
+func (rs *replicaSet) markUnhealthy(r *replica, err error) {
+	if r.healthy.Swap(false) {
+		logger.GetLogger().Info().Err(err).Str("replica", r.name).Msg("replica marked unhealthy")
+	}
+}
+
+func (rs *replicaSet) closePools() {
+	for _, r := range rs.replicas {
+		r.pool.Close()
+	}
+}
+
+func (rs *replicaSet) close() {
+	if rs == nil {
+		return
+	}
+
+	rs.cancel()
+	<-rs.done
+
+	rs.closePools()
+}
+
+type sessionKey struct{}
+
+type session struct {
+	wrote atomic.Bool
+}
+
+// WithSession returns context that tracks writes made through it. Once a
+// write has been committed, reads with the same context go to the primary so
+// callers always see their own changes.
+func WithSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionKey{}, &session{})
+}
+
+func markWrite(ctx context.Context) {
+	if s, ok := ctx.Value(sessionKey{}).(*session); ok {
+		s.wrote.Store(true)
+	}
+}
+
+func wroteInSession(ctx context.Context) bool {
+	s, ok := ctx.Value(sessionKey{}).(*session)
+
+	return ok && s.wrote.Load()
+}
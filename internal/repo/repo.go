@@ -3,21 +3,166 @@ package repo
 import (
 	"context"
 	"project/internal/model"
+	"time"
 )
 
+// UnitOfWork runs several repository calls atomically.
+type UnitOfWork interface {
+	// Atomic calls fn with a Repo bound to a single transaction. All calls made
+	// through that Repo are committed together when fn returns nil and rolled
+	// back otherwise. Atomic calls on the bound Repo join the outer unit.
+	// fn may be rerun on serialization failures, so it must not have side
+	// effects outside the repo.
+	Atomic(context.Context, func(context.Context, Repo) error) error
+}
+
 type Repo interface {
-	AddNewChat(context.Context, int, int, string, string, int) error
-	GetChatsInfoByOwnerId(context.Context, int) ([]model.ChatInfo, error)
-	DisableChat(context.Context, int) error
+	UnitOfWork
+
+	// AddNewChat adds chat, reusing the row of a deleted chat with the same
+	// id. It returns ErrConflict if the chat exists.
+	AddNewChat(context.Context, model.AddNewChat) error
+	// ClearDeletedChat removes what a deleted chat leaves behind, it is
+	// called before the chat is added again.
+	ClearDeletedChat(context.Context, int) error
+	GetChatsInfoByOwnerId(context.Context, int, bool) ([]model.ChatInfo, error)
+	// LockChat returns chat and locks it inside a unit of work.
+	LockChat(context.Context, int) (model.ChatInfo, error)
+	// LockChatShared returns chat and keeps its status from changing inside
+	// a unit of work, without blocking other readers.
+	LockChatShared(context.Context, int) (model.ChatInfo, error)
+	SetChatStatus(context.Context, int, string, string) error
+	// ExtendSubscriptions moves expiry of active subscriptions of chat by the
+	// time passed since it was paused.
+	ExtendSubscriptions(context.Context, int, time.Time) (int, error)
+	CountPaidSubscribers(context.Context, int) (int, error)
+	// DeleteChat revokes all remaining access to chat.
+	DeleteChat(context.Context, int) (int, error)
+
+	GetChatMemberRole(context.Context, int, int) (string, error)
+	AddChatMember(context.Context, model.ChatMember) (model.ChatMember, error)
+	GetChatMembers(context.Context, int) ([]model.ChatMember, error)
+	RemoveChatMember(context.Context, int, int) error
+	AddTransfer(context.Context, model.ChatTransfer) (model.ChatTransfer, error)
+	// GetPendingTransfer returns pending transfer of chat and locks it inside
+	// a unit of work.
+	GetPendingTransfer(context.Context, int) (model.ChatTransfer, error)
+	GetPendingTransfers(context.Context, int) ([]model.ChatTransfer, error)
+	ResolveTransfer(context.Context, model.ChatTransfer) (model.ChatTransfer, error)
+
+	GetCatalog(context.Context, model.CatalogQuery) ([]model.CatalogChat, error)
+	// RefreshSubscriberCounts updates subscriber counts the catalog is sorted
+	// by and returns the number of chats whose count changed.
+	RefreshSubscriberCounts(context.Context) (int, error)
+	ChangeCatalogInfo(context.Context, int, string, []string) error
+
+	ChangeRating(context.Context, model.RatingChange) (model.RatingChange, error)
+	GetRatingHistory(context.Context, int) ([]model.RatingChange, error)
+	IsAgeVerified(context.Context, int) (bool, error)
+	SetAgeVerified(context.Context, int, bool) error
 	ChangeDescription(context.Context, int, string) error
-	ChangePrice(context.Context, int, int) error
+	ChangePrice(context.Context, model.ChangePrice) (model.PriceChange, error)
+	GetPriceHistory(context.Context, int) ([]model.PriceChange, error)
+	GetLockedPrice(context.Context, int, int) (int, bool, error)
+	ChangeTrial(context.Context, int, int) error
+	GetChatTrialDays(context.Context, int) (int, error)
 	GetAllSlaves(context.Context, int) ([]int, error)
 
 	NewSubscribe(context.Context, int, int) error
+	StartTrial(context.Context, int, int) (bool, error)
+	NewTrialSubscribe(context.Context, int, int, time.Time) error
 	GetAllSubsciptions(context.Context, int) ([]int, error)
-	Pay(context.Context, int, int) error
+	Pay(context.Context, model.Payment) (model.Payment, error)
 	IsSubscribeExists(context.Context, int, int) (bool, error)
 	IsPaid(context.Context, int, int) (bool, error)
+	GetAccess(context.Context, int, int) (model.Access, error)
+
+	// ClaimEvents leases a batch of pending outbox events for the given
+	// duration. Events not marked delivered or failed by then are claimed
+	// again.
+	ClaimEvents(context.Context, int, time.Duration) ([]model.OutboxEvent, error)
+	MarkSinkDelivered(context.Context, string, []int64) error
+	MarkEventsDelivered(context.Context, []int64) error
+	// MarkEventsFailed reschedules events with exponential backoff.
+	MarkEventsFailed(context.Context, []int64, string) error
+	ExpireSubscriptions(context.Context, time.Time) (int, error)
+	GetEvents(context.Context, model.EventCursor, model.EventFilter, int) ([]model.Event, error)
+	// GetEventCursor returns stream position to read only events that are not
+	// visible yet.
+	GetEventCursor(context.Context) (model.EventCursor, error)
+
+	SetOwnerSecret(context.Context, int, string, bool) (string, error)
+	AddWebhook(context.Context, model.AddWebhook) (int64, error)
+	GetWebhooks(context.Context, int) ([]model.Webhook, error)
+	DeleteWebhook(context.Context, int64, int) error
+	// ClaimWebhookDeliveries returns due deliveries and hides them from other
+	// workers for the lease duration.
+	ClaimWebhookDeliveries(context.Context, int, time.Duration) ([]model.PendingWebhookDelivery, error)
+	RecordWebhookAttempt(context.Context, int64, model.WebhookAttempt, string, time.Time) error
+	GetWebhookDeliveries(context.Context, int64, int, int) ([]model.WebhookDelivery, error)
+	Redeliver(context.Context, int64, int) error
+
+	// GetExpiringSubscriptions returns paid subscriptions expiring within
+	// (now+from, now+to] not yet reminded about for window to.
+	GetExpiringSubscriptions(context.Context, time.Time, time.Duration, time.Duration, int) ([]model.ExpiryReminder, error)
+	// AddReminder records reminder and reports false if it was already sent.
+	AddReminder(context.Context, model.ExpiryReminder) (bool, error)
+	// DeleteReminder forgets reminder, so it is sent again on the next run.
+	DeleteReminder(context.Context, model.ExpiryReminder) error
+
+	AddPlan(context.Context, model.Plan) (int64, error)
+	GetPlans(context.Context, int) ([]model.Plan, error)
+	GetPlan(context.Context, int64, int) (model.Plan, error)
+	UpdatePlan(context.Context, model.Plan) error
+	DeletePlan(context.Context, int64, int) error
+
+	GetChatPrice(context.Context, int) (model.Money, error)
+	CountPayments(context.Context, int, int) (int, error)
+	AddPromoCode(context.Context, model.PromoCode) error
+	GetPromoCodes(context.Context, int) ([]model.PromoCode, error)
+	GetPromoCode(context.Context, int, string) (model.PromoCode, error)
+	// LockPromoCode returns active code and locks it inside a unit of work.
+	LockPromoCode(context.Context, int, string) (model.PromoCode, error)
+	DeletePromoCode(context.Context, int, string) error
+	CountPromoRedemptions(context.Context, int, string, int) (int, error)
+	RedeemPromoCode(context.Context, int, string, int, int64, int64) error
+
+	GetChat(context.Context, int) (model.ChatInfo, error)
+	AddInvoice(context.Context, model.Invoice) (model.Invoice, error)
+	GetInvoice(context.Context, string) (model.Invoice, error)
+	MarkInvoicePaid(context.Context, int64, int64) error
+	GetPayment(context.Context, int, int64) (model.Payment, error)
+	AddRefund(context.Context, model.Refund, float64) (model.Refund, error)
+	AddPendingRefund(context.Context, model.Refund) (model.Refund, error)
+	CompleteRefund(context.Context, model.Refund, float64) (model.Refund, error)
+	FailRefund(context.Context, int64, string) error
+	GetRefund(context.Context, int64) (model.Refund, error)
+	GetRefunds(context.Context, int) ([]model.Refund, error)
+
+	LockOwnerPayouts(context.Context, int) error
+	GetBalanceTotals(context.Context, int, time.Time) ([]model.BalanceTotals, error)
+	AddPayout(context.Context, model.Payout) (model.Payout, error)
+	GetPayouts(context.Context, int) ([]model.Payout, error)
+	GetPayout(context.Context, int64) (model.Payout, error)
+	UpdatePayout(context.Context, model.Payout) (model.Payout, error)
+	GetStatement(context.Context, int, time.Time, time.Time) ([]model.StatementEntry, error)
+
+	GetAnalytics(context.Context, model.AnalyticsRequest) (model.Analytics, error)
+
+	// ExportSubscribers and ExportPayments call fn for every row as it is
+	// read, stopping at the first error fn returns.
+	ExportSubscribers(context.Context, int, func(model.SubscriberExport) error) error
+	ExportPayments(context.Context, model.PaymentsExportRequest, func(model.Payment) error) error
+
+	AddInviteToken(context.Context, model.InviteToken) error
+	GetActiveInviteToken(context.Context, int, int) (model.InviteToken, error)
+	GetInviteToken(context.Context, string) (model.InviteToken, error)
+	RedeemInviteToken(context.Context, string, int) error
+	// ReleaseInviteToken undoes redemption of token by user.
+	ReleaseInviteToken(context.Context, string, int) error
+
+	Ping(context.Context) error
+	CheckMigrations(context.Context) error
 
 	Close() error
 }
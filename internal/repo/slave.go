@@ -14,10 +14,43 @@ const newSubscribeQuery = `
 	($1, $2, false, $3)
 `
 
+const startTrialQuery = `
+	insert into trials (chat_id, user_id)
+	values
+	($1, $2)
+	on conflict do nothing
+`
+
+const newTrialSubscribeQuery = `
+	insert into users (chat_id, user_id, is_active, is_trial, expired_date)
+	values
+	($1, $2, true, true, $3)
+`
+
+// payQuery extends access by $4 months from its current end, or from now if
+// the subscription is not active. A trial turns into paid access starting
+// when the trial ends. Null $4 grants lifetime access. It returns the period
+// the payment covers.
 const payQuery = `
-	update users set is_active = true where chat_id = $1 and user_id = $2
+	update users set
+		is_active = true,
+		is_trial = false,
+		plan_id = nullif($3, 0),
+		expired_date = case
+			when $4::integer is null then timestamptz '9999-12-31 00:00:00+00'
+			when is_active then greatest(expired_date, now()) + make_interval(months => $4::integer)
+			else now() + make_interval(months => $4::integer)
+		end
+	where chat_id = $1 and user_id = $2
+	returning
+		case when $4::integer is null then now() else expired_date - make_interval(months => $4::integer) end,
+		expired_date
 `
 
 const isPaidQuery = `
-	select is_active from users where chat_id = $1 and user_id = $2
+	select is_active and not is_trial from users where chat_id = $1 and user_id = $2
+`
+
+const getAccessQuery = `
+	select is_active, is_trial, expired_date from users where chat_id = $1 and user_id = $2
 `
@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"project/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	maxTxAttempts = 3
+	txRetryDelay  = 50 * time.Millisecond
+
+	serializationFailureCode = "40001"
+	deadlockDetectedCode     = "40P01"
+)
+
+var (
+	readWrite = pgx.TxOptions{}
+	readOnly  = pgx.TxOptions{AccessMode: pgx.ReadOnly}
+)
+
+func (p *pg) Atomic(ctx context.Context, fn func(context.Context, Repo) error) error {
+	return p.WithTx(ctx, readWrite, func(ctx context.Context, tx pgx.Tx) error {
+		return fn(ctx, &pg{pool: p.pool, queryTimeout: p.queryTimeout, tx: tx})
+	})
+}
+
+// WithTx runs fn inside a transaction started with opts. fn must use the
+// context it is given, which carries the per-query deadline. The transaction is
+// committed if fn succeeds and rolled back otherwise. Serialization failures
+// and deadlocks restart the whole transaction, so fn must be safe to rerun.
+//
+// Inside a unit of work fn runs in a savepoint of the outer transaction and
+// opts are ignored, retries are left to the outermost call.
+func (p *pg) WithTx(ctx context.Context, opts pgx.TxOptions, fn func(context.Context, pgx.Tx) error) error {
+	if p.tx != nil {
+		return pgx.BeginFunc(ctx, p.tx, func(tx pgx.Tx) error {
+			return fn(ctx, tx)
+		})
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := p.runTx(ctx, opts, fn)
+		if err == nil || attempt >= maxTxAttempts || !isRetryable(err) {
+			return err
+		}
+
+		logger.GetLogger().Debug().Err(err).Int("attempt", attempt).Msg("retrying transaction")
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to retry transaction. %w", ctx.Err())
+		case <-time.After(txRetryDelay * time.Duration(attempt)):
+		}
+	}
+}
+
+func (p *pg) runTx(ctx context.Context, opts pgx.TxOptions, fn func(context.Context, pgx.Tx) error) error {
+	if p.queryTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, p.queryTimeout)
+		defer cancel()
+	}
+
+	tx, err := p.begin(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction. %w", err)
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.GetLogger().Err(err).Msg("failed to rollback transaction")
+		}
+	}()
+
+	if err := fn(ctx, tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction. %w", err)
+	}
+
+	if opts.AccessMode != pgx.ReadOnly {
+		markWrite(ctx)
+	}
+
+	return nil
+}
+
+// begin starts read-only transactions on a replica unless the session has
+// already written something, falling back to the primary when the replica
+// cannot be reached.
+func (p *pg) begin(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	if opts.AccessMode == pgx.ReadOnly && !wroteInSession(ctx) {
+		if r := p.replicas.pick(); r != nil {
+			tx, err := r.pool.BeginTx(ctx, opts)
+			if err == nil {
+				return tx, nil
+			}
+
+			if ctx.Err() != nil {
+				return nil, err
+			}
+
+			p.replicas.markUnhealthy(r, err)
+		}
+	}
+
+	return p.pool.BeginTx(ctx, opts)
+}
+
+func isRetryable(err error) bool {
+	var pgErr *pgconn.PgError
+
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	return pgErr.Code == serializationFailureCode || pgErr.Code == deadlockDetectedCode
+}
@@ -0,0 +1,107 @@
+package repo
+
+// setOwnerSecretQuery keeps the existing secret unless $3 asks to replace it.
+const setOwnerSecretQuery = `
+	insert into owner_secrets (owner_id, secret)
+	values
+	($1, $2)
+	on conflict (owner_id) do update
+	set secret = case when $3 then excluded.secret else owner_secrets.secret end
+	returning secret
+`
+
+const addWebhookQuery = `
+	insert into webhooks (chat_id, owner_id, url, events)
+	select chat_id, owner_id, $3, $4 from chat where chat_id = $1 and owner_id = $2
+	returning id
+`
+
+const getWebhooksQuery = `
+	select id, chat_id, owner_id, url, events, created_at
+	from webhooks
+	where chat_id = $1 and is_active
+	order by id
+`
+
+// webhookOwnedQuery limits webhook w to those whose owner still owns the
+// chat.
+const webhookOwnedQuery = `
+	exists (select 1 from chat_members m where m.chat_id = w.chat_id and m.user_id = w.owner_id and m.role = 'owner')
+`
+
+const deleteWebhookQuery = `
+	update webhooks w set is_active = false where w.id = $1 and w.owner_id = $2 and w.is_active and ` + webhookOwnedQuery + `
+	returning w.chat_id
+`
+
+// cancelInactiveWebhookDeliveriesQuery cancels deliveries still pending for
+// deactivated webhooks of chat $1, so events stop going to their urls.
+const cancelInactiveWebhookDeliveriesQuery = `
+	update webhook_deliveries d set status = 'cancelled'
+	from webhooks w
+	where w.id = d.webhook_id and w.chat_id = $1 and not w.is_active and d.status = 'pending'
+`
+
+// enqueueWebhookDeliveriesQuery queues event $1 for every webhook of the chat
+// subscribed to it.
+const enqueueWebhookDeliveriesQuery = `
+	insert into webhook_deliveries (webhook_id, event_id)
+	select id, $1 from webhooks
+	where chat_id = $2 and is_active and (cardinality(events) = 0 or $3 = any(events))
+	on conflict (webhook_id, event_id) do nothing
+`
+
+// claimWebhookDeliveriesQuery leases due deliveries until $2 so concurrent
+// workers skip them while they are being sent.
+const claimWebhookDeliveriesQuery = `
+	with due as (
+		select d.id from webhook_deliveries d
+		join webhooks w on w.id = d.webhook_id and w.is_active
+		where d.status = 'pending' and d.next_attempt_at <= now()
+		order by d.next_attempt_at
+		limit $1
+		for update of d skip locked
+	)
+	update webhook_deliveries d set next_attempt_at = $2
+	from due, webhooks w, outbox o, owner_secrets s
+	where d.id = due.id and w.id = d.webhook_id and w.is_active and o.id = d.event_id and s.owner_id = w.owner_id
+	returning d.id, d.attempts, w.url, s.secret,
+		o.id, o.event_type, o.chat_id, coalesce(o.user_id, 0), o.payload, o.created_at
+`
+
+const addWebhookAttemptQuery = `
+	insert into webhook_delivery_attempts (delivery_id, attempted_at, response_status, error, duration_ms)
+	values
+	($1, $2, nullif($3, 0), nullif($4, ''), $5)
+`
+
+const updateWebhookDeliveryQuery = `
+	update webhook_deliveries set
+		attempts = attempts + 1,
+		status = $2,
+		next_attempt_at = $3,
+		delivered_at = case when $2 = 'delivered' then now() end
+	where id = $1
+`
+
+const getWebhookDeliveriesQuery = `
+	select d.id, d.webhook_id, d.event_id, o.event_type, d.status, d.attempts, d.next_attempt_at, d.created_at, d.delivered_at
+	from webhook_deliveries d
+	join webhooks w on w.id = d.webhook_id
+	join outbox o on o.id = d.event_id
+	where d.webhook_id = $1 and w.owner_id = $2 and ` + webhookOwnedQuery + `
+	order by d.id desc
+	limit $3
+`
+
+const getWebhookAttemptsQuery = `
+	select delivery_id, attempted_at, coalesce(response_status, 0), coalesce(error, ''), duration_ms
+	from webhook_delivery_attempts
+	where delivery_id = any($1)
+	order by id
+`
+
+const redeliverQuery = `
+	update webhook_deliveries d set status = 'pending', next_attempt_at = now()
+	from webhooks w
+	where d.id = $1 and w.id = d.webhook_id and w.owner_id = $2 and ` + webhookOwnedQuery
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project/internal/model"
+	"time"
+)
+
+const (
+	defaultAnalyticsPeriod = 30 * 24 * time.Hour
+	maxAnalyticsPoints     = 400
+)
+
+// granularityStep is the shortest length of a period of each granularity.
+var granularityStep = map[string]time.Duration{
+	model.GranularityDay:   24 * time.Hour,
+	model.GranularityWeek:  7 * 24 * time.Hour,
+	model.GranularityMonth: 28 * 24 * time.Hour,
+}
+
+func (s *service) GetAnalytics(ctx context.Context, req model.AnalyticsRequest) (model.Analytics, error) {
+	if req.Granularity == "" {
+		req.Granularity = model.GranularityDay
+	}
+
+	step, ok := granularityStep[req.Granularity]
+	if !ok {
+		return model.Analytics{}, fmt.Errorf("unknown granularity %q. %w", req.Granularity, model.ErrInvalidArgument)
+	}
+
+	if req.To.IsZero() {
+		req.To = time.Now()
+	}
+
+	if req.From.IsZero() {
+		req.From = req.To.Add(-defaultAnalyticsPeriod)
+	}
+
+	if !req.To.After(req.From) {
+		return model.Analytics{}, fmt.Errorf("from must be before to. %w", model.ErrInvalidArgument)
+	}
+
+	if req.To.Sub(req.From)/step > maxAnalyticsPoints {
+		return model.Analytics{}, fmt.Errorf("period has more than %d points of %s. %w", maxAnalyticsPoints, req.Granularity, model.ErrInvalidArgument)
+	}
+
+	res, err := s.repo.GetAnalytics(ctx, req)
+	if err != nil {
+		return model.Analytics{}, fmt.Errorf("failed to get analytics in repo. %w", err)
+	}
+
+	if res.Subscribed > 0 {
+		res.Conversion = float64(res.Converted) / float64(res.Subscribed)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"project/internal/model"
+)
+
+func TestGetAnalyticsPeriod(t *testing.T) {
+	to := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		req  model.AnalyticsRequest
+		want error
+	}{
+		{name: "defaults", req: model.AnalyticsRequest{To: to}},
+		{name: "year by week", req: model.AnalyticsRequest{From: to.AddDate(-1, 0, 0), To: to, Granularity: model.GranularityWeek}},
+		{name: "year by day", req: model.AnalyticsRequest{From: to.AddDate(-2, 0, 0), To: to}, want: model.ErrInvalidArgument},
+		{name: "from after to", req: model.AnalyticsRequest{From: to, To: to.Add(-time.Hour)}, want: model.ErrInvalidArgument},
+		{name: "unknown granularity", req: model.AnalyticsRequest{To: to, Granularity: "hour"}, want: model.ErrInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeRepo()
+			s := &service{repo: r}
+
+			_, err := s.GetAnalytics(context.Background(), tt.req)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("GetAnalytics() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAnalyticsDefaults(t *testing.T) {
+	r := newFakeRepo()
+	s := &service{repo: r}
+
+	to := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := s.GetAnalytics(context.Background(), model.AnalyticsRequest{OwnerId: ownerId, To: to}); err != nil {
+		t.Fatalf("GetAnalytics() error = %v", err)
+	}
+
+	req := r.analyticsReq
+	if req.Granularity != model.GranularityDay || !req.From.Equal(to.Add(-defaultAnalyticsPeriod)) || !req.To.Equal(to) {
+		t.Fatalf("GetAnalytics() requested %+v, want %s since %s", req, model.GranularityDay, to.Add(-defaultAnalyticsPeriod))
+	}
+}
+
+func TestGetAnalyticsConversion(t *testing.T) {
+	tests := []struct {
+		name       string
+		subscribed int
+		converted  int
+		want       float64
+	}{
+		{name: "no subscriptions", want: 0},
+		{name: "some converted", subscribed: 8, converted: 2, want: 0.25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeRepo()
+			r.analytics = model.Analytics{Subscribed: tt.subscribed, Converted: tt.converted}
+
+			s := &service{repo: r}
+
+			res, err := s.GetAnalytics(context.Background(), model.AnalyticsRequest{OwnerId: ownerId})
+			if err != nil {
+				t.Fatalf("GetAnalytics() error = %v", err)
+			}
+
+			if res.Conversion != tt.want {
+				t.Fatalf("GetAnalytics() conversion = %v, want %v", res.Conversion, tt.want)
+			}
+		})
+	}
+}
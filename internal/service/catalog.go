@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"project/internal/logger"
+	"project/internal/model"
+	"project/internal/repo"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	defaultCatalogLimit = 20
+	maxCatalogLimit     = 100
+	maxCatalogQuery     = 200
+	maxCategoryLength   = 32
+	maxTagLength        = 32
+	maxTags             = 10
+
+	// subscriberCountPeriod is how often subscriber counts shown in the
+	// catalog are refreshed.
+	subscriberCountPeriod = 5 * time.Minute
+)
+
+var catalogSorts = []string{
+	model.CatalogSortPopular,
+	model.CatalogSortPriceAsc,
+	model.CatalogSortPriceDesc,
+	model.CatalogSortRelevance,
+}
+
+// GetCatalog returns a page of active chats. Search results are ordered by
+// relevance unless another sort is asked for, others by popularity. Age
+// restricted chats are listed only to users who verified their age.
+func (s *service) GetCatalog(ctx context.Context, req model.CatalogRequest) (model.CatalogPage, error) {
+	query := model.CatalogQuery{
+		Query:    strings.TrimSpace(req.Query),
+		Category: normalizeLabel(req.Category),
+		Tags:     make([]string, 0, len(req.Tags)),
+		Currency: model.NormalizeCurrency(req.Currency),
+		Sort:     req.Sort,
+		Limit:    req.Limit,
+	}
+
+	if utf8.RuneCountInString(query.Query) > maxCatalogQuery {
+		return model.CatalogPage{}, fmt.Errorf("query must be at most %d characters. %w", maxCatalogQuery, model.ErrInvalidArgument)
+	}
+
+	for _, tag := range req.Tags {
+		if tag = normalizeLabel(tag); tag != "" {
+			query.Tags = append(query.Tags, tag)
+		}
+	}
+
+	if query.Sort == "" {
+		query.Sort = model.CatalogSortPopular
+
+		if query.Query != "" {
+			query.Sort = model.CatalogSortRelevance
+		}
+	}
+
+	if !slices.Contains(catalogSorts, query.Sort) {
+		return model.CatalogPage{}, fmt.Errorf("unknown sort %q. %w", query.Sort, model.ErrInvalidArgument)
+	}
+
+	isPriceSort := query.Sort == model.CatalogSortPriceAsc || query.Sort == model.CatalogSortPriceDesc
+
+	if isPriceSort && query.Currency == "" {
+		return model.CatalogPage{}, fmt.Errorf("currency is required to sort by price. %w", model.ErrInvalidArgument)
+	}
+
+	if query.Currency != "" {
+		if err := model.ValidateCurrency(query.Currency); err != nil {
+			return model.CatalogPage{}, err
+		}
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultCatalogLimit
+	}
+
+	if query.Limit > maxCatalogLimit {
+		query.Limit = maxCatalogLimit
+	}
+
+	if req.Cursor != "" {
+		after, err := decodeCatalogCursor(req.Cursor)
+		if err != nil {
+			return model.CatalogPage{}, err
+		}
+
+		query.After = &after
+	}
+
+	query.HiddenRatings = make([]string, 0, len(model.AgeRestrictedRatings))
+
+	verified := false
+
+	if req.UserId != 0 {
+		var err error
+
+		verified, err = s.repo.IsAgeVerified(ctx, req.UserId)
+		if err != nil {
+			return model.CatalogPage{}, fmt.Errorf("failed to check age verification in repo. %w", err)
+		}
+	}
+
+	if !verified {
+		query.HiddenRatings = append(query.HiddenRatings, model.AgeRestrictedRatings...)
+	}
+
+	// One extra chat tells whether there is a next page.
+	query.Limit++
+
+	chats, err := s.repo.GetCatalog(ctx, query)
+	if err != nil {
+		return model.CatalogPage{}, fmt.Errorf("failed to get catalog in repo. %w", err)
+	}
+
+	page := model.CatalogPage{
+		Chats: chats,
+	}
+
+	if len(chats) == query.Limit {
+		page.Chats = chats[:len(chats)-1]
+
+		last := page.Chats[len(page.Chats)-1]
+
+		page.NextCursor, err = encodeCatalogCursor(model.CatalogCursor{SortKey: last.SortKey, ChatId: last.ChatId})
+		if err != nil {
+			return model.CatalogPage{}, err
+		}
+	}
+
+	if page.Chats == nil {
+		page.Chats = make([]model.CatalogChat, 0)
+	}
+
+	return page, nil
+}
+
+// refreshSubscriberCounts recounts subscribers of chats, popularity in the
+// catalog lags behind subscriptions by up to subscriberCountPeriod.
+func (s *service) refreshSubscriberCounts(ctx context.Context) error {
+	n, err := s.repo.RefreshSubscriberCounts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to refresh subscriber counts in repo. %w", err)
+	}
+
+	if n > 0 {
+		logger.GetLogger().Debug().Int("count", n).Msg("refreshed subscriber counts")
+	}
+
+	return nil
+}
+
+func encodeCatalogCursor(c model.CatalogCursor) (string, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cursor. %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func decodeCatalogCursor(cursor string) (model.CatalogCursor, error) {
+	var c model.CatalogCursor
+
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(b, &c)
+	}
+
+	if err != nil {
+		return model.CatalogCursor{}, fmt.Errorf("invalid cursor. %w", model.ErrInvalidArgument)
+	}
+
+	return c, nil
+}
+
+// normalizeLabel makes category and tags match regardless of case and
+// surrounding spaces.
+func normalizeLabel(label string) string {
+	return strings.ToLower(strings.TrimSpace(label))
+}
+
+// ChangeCatalogInfo sets category and tags of chat. Empty category and no
+// tags remove them.
+func (s *service) ChangeCatalogInfo(ctx context.Context, change model.ChangeCatalogInfo) error {
+	category := normalizeLabel(change.Category)
+
+	if utf8.RuneCountInString(category) > maxCategoryLength {
+		return fmt.Errorf("category must be at most %d characters. %w", maxCategoryLength, model.ErrInvalidArgument)
+	}
+
+	tags := make([]string, 0, len(change.Tags))
+
+	for _, tag := range change.Tags {
+		tag = normalizeLabel(tag)
+
+		if tag == "" || slices.Contains(tags, tag) {
+			continue
+		}
+
+		if utf8.RuneCountInString(tag) > maxTagLength {
+			return fmt.Errorf("tags must be at most %d characters. %w", maxTagLength, model.ErrInvalidArgument)
+		}
+
+		tags = append(tags, tag)
+	}
+
+	if len(tags) > maxTags {
+		return fmt.Errorf("chat can have at most %d tags. %w", maxTags, model.ErrInvalidArgument)
+	}
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		if err := authorize(ctx, r, change.ChatId, change.ActorId, model.RoleAdmin); err != nil {
+			return err
+		}
+
+		return r.ChangeCatalogInfo(ctx, change.ChatId, category, tags)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to change catalog info. %w", err)
+	}
+
+	return nil
+}
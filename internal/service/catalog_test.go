@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"project/internal/model"
+)
+
+func newCatalogService() (*service, *fakeRepo) {
+	r := newFakeRepo()
+
+	// Chats 2 and 3 share the sort key, so pages break ties by chat id.
+	r.catalog = []model.CatalogChat{
+		{ChatId: 4, SortKey: 3, ContentRating: model.RatingMature},
+		{ChatId: 3, SortKey: 2, ContentRating: model.RatingGeneral},
+		{ChatId: 1, SortKey: 1, ContentRating: model.RatingGeneral},
+		{ChatId: 2, SortKey: 2, ContentRating: model.RatingAdult},
+		{ChatId: 5, SortKey: 4, ContentRating: model.RatingGeneral},
+	}
+	r.verified[subscriberId] = true
+
+	return &service{repo: r}, r
+}
+
+// catalogIds walks the catalog page by page.
+func catalogIds(t *testing.T, s *service, req model.CatalogRequest) []int {
+	t.Helper()
+
+	var ids []int
+
+	for {
+		page, err := s.GetCatalog(context.Background(), req)
+		if err != nil {
+			t.Fatalf("GetCatalog() error = %v", err)
+		}
+
+		if len(page.Chats) > req.Limit {
+			t.Fatalf("GetCatalog() returned %d chats, limit is %d", len(page.Chats), req.Limit)
+		}
+
+		for _, c := range page.Chats {
+			ids = append(ids, c.ChatId)
+		}
+
+		if page.NextCursor == "" {
+			return ids
+		}
+
+		req.Cursor = page.NextCursor
+	}
+}
+
+func TestGetCatalogPages(t *testing.T) {
+	tests := []struct {
+		name   string
+		userId int
+		limit  int
+		want   []int
+	}{
+		{name: "anonymous", limit: 2, want: []int{1, 3, 4, 5}},
+		{name: "unverified user", userId: ownerId, limit: 2, want: []int{1, 3, 4, 5}},
+		{name: "verified user", userId: subscriberId, limit: 2, want: []int{1, 2, 3, 4, 5}},
+		{name: "full last page", userId: subscriberId, limit: 5, want: []int{1, 2, 3, 4, 5}},
+		{name: "one page", limit: 10, want: []int{1, 3, 4, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newCatalogService()
+
+			ids := catalogIds(t, s, model.CatalogRequest{UserId: tt.userId, Limit: tt.limit})
+			if !slices.Equal(ids, tt.want) {
+				t.Fatalf("GetCatalog() chats = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetCatalogInvalidCursor(t *testing.T) {
+	s, _ := newCatalogService()
+
+	_, err := s.GetCatalog(context.Background(), model.CatalogRequest{Cursor: "not a cursor"})
+	if !errors.Is(err, model.ErrInvalidArgument) {
+		t.Fatalf("GetCatalog() error = %v, want %v", err, model.ErrInvalidArgument)
+	}
+}
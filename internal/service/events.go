@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project/internal/model"
+)
+
+const subscriptionEventsLimit = 100
+
+// GetSubscriptionEvents checks access on every call, so a stream ends once
+// the actor loses it.
+func (s *service) GetSubscriptionEvents(ctx context.Context, after model.EventCursor, req model.EventStream) ([]model.Event, error) {
+	if req.ActorId <= 0 {
+		return nil, fmt.Errorf("actor_id is required. %w", model.ErrInvalidArgument)
+	}
+
+	if req.ChatId == 0 && req.OwnerId == 0 {
+		return nil, fmt.Errorf("chat_id or owner_id is required. %w", model.ErrInvalidArgument)
+	}
+
+	if req.OwnerId != 0 && req.OwnerId != req.ActorId {
+		return nil, fmt.Errorf("user %d is not owner %d. %w", req.ActorId, req.OwnerId, model.ErrForbidden)
+	}
+
+	if req.ChatId != 0 {
+		if err := authorize(ctx, s.repo, req.ChatId, req.ActorId, model.RoleViewer); err != nil {
+			return nil, err
+		}
+	}
+
+	filter := model.EventFilter{
+		ChatId:  req.ChatId,
+		OwnerId: req.OwnerId,
+		Types:   model.SubscriptionEventTypes,
+	}
+
+	events, err := s.repo.GetEvents(ctx, after, filter, subscriptionEventsLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get events in repo. %w", err)
+	}
+
+	return events, nil
+}
+
+func (s *service) GetEventCursor(ctx context.Context) (model.EventCursor, error) {
+	cursor, err := s.repo.GetEventCursor(ctx)
+	if err != nil {
+		return model.EventCursor{}, fmt.Errorf("failed to get event cursor in repo. %w", err)
+	}
+
+	return cursor, nil
+}
+
+func (s *service) WaitEvents() <-chan struct{} {
+	return s.broker.Wait()
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"project/internal/model"
+)
+
+func TestGetSubscriptionEventsAccess(t *testing.T) {
+	const viewerId = 13
+
+	tests := []struct {
+		name string
+		req  model.EventStream
+		want error
+	}{
+		{name: "viewer of chat", req: model.EventStream{ChatId: lifecycleChatId, ActorId: viewerId}},
+		{name: "owner of chat", req: model.EventStream{ChatId: lifecycleChatId, ActorId: ownerId}},
+		{name: "own events", req: model.EventStream{OwnerId: ownerId, ActorId: ownerId}},
+		{name: "subscriber of chat", req: model.EventStream{ChatId: lifecycleChatId, ActorId: subscriberId}, want: model.ErrForbidden},
+		{name: "other owner", req: model.EventStream{OwnerId: ownerId, ActorId: viewerId}, want: model.ErrForbidden},
+		{name: "viewer of chat with other owner", req: model.EventStream{ChatId: lifecycleChatId, OwnerId: ownerId, ActorId: viewerId}, want: model.ErrForbidden},
+		{name: "no actor", req: model.EventStream{ChatId: lifecycleChatId}, want: model.ErrInvalidArgument},
+		{name: "no filter", req: model.EventStream{ActorId: ownerId}, want: model.ErrInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, r := newLifecycleService(t)
+
+			r.members[[2]int{lifecycleChatId, viewerId}] = model.RoleViewer
+
+			_, err := s.GetSubscriptionEvents(context.Background(), model.EventCursor{}, tt.req)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("GetSubscriptionEvents() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
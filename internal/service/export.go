@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project/internal/model"
+	"time"
+)
+
+func (s *service) ExportSubscribers(ctx context.Context, req model.ChatActor, fn func(model.SubscriberExport) error) error {
+	if err := authorize(ctx, s.repo, req.ChatId, req.ActorId, model.RoleViewer); err != nil {
+		return err
+	}
+
+	if err := s.repo.ExportSubscribers(ctx, req.ChatId, fn); err != nil {
+		return fmt.Errorf("failed to export subscribers in repo. %w", err)
+	}
+
+	return nil
+}
+
+// ExportPayments exports owner payments made in [From, To). To defaults to
+// now and zero From exports all payments before it.
+func (s *service) ExportPayments(ctx context.Context, req model.PaymentsExportRequest, fn func(model.Payment) error) error {
+	if req.ActorId <= 0 {
+		return fmt.Errorf("actor_id is required. %w", model.ErrInvalidArgument)
+	}
+
+	if req.OwnerId != req.ActorId {
+		return fmt.Errorf("user %d is not owner %d. %w", req.ActorId, req.OwnerId, model.ErrForbidden)
+	}
+
+	if req.To.IsZero() {
+		req.To = time.Now()
+	}
+
+	if !req.To.After(req.From) {
+		return fmt.Errorf("from must be before to. %w", model.ErrInvalidArgument)
+	}
+
+	if err := s.repo.ExportPayments(ctx, req, fn); err != nil {
+		return fmt.Errorf("failed to export payments in repo. %w", err)
+	}
+
+	return nil
+}
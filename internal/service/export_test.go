@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"project/internal/model"
+)
+
+func TestExportPaymentsAccess(t *testing.T) {
+	tests := []struct {
+		name string
+		req  model.PaymentsExportRequest
+		want error
+	}{
+		{name: "owner", req: model.PaymentsExportRequest{OwnerId: ownerId, ActorId: ownerId}},
+		{name: "other user", req: model.PaymentsExportRequest{OwnerId: ownerId, ActorId: adminId}, want: model.ErrForbidden},
+		{name: "no actor", req: model.PaymentsExportRequest{OwnerId: ownerId}, want: model.ErrInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{repo: newFakeRepo()}
+
+			err := s.ExportPayments(context.Background(), tt.req, func(model.Payment) error { return nil })
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("ExportPayments() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,666 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"project/internal/model"
+	"project/internal/repo"
+)
+
+// fakeRepo keeps in memory the state of the methods tests need. Calling any
+// other method panics on the nil embedded interface.
+type fakeRepo struct {
+	repo.Repo
+
+	mu sync.Mutex
+
+	// expiring are subscriptions returned by GetExpiringSubscriptions for
+	// their Window unless a reminder is recorded.
+	expiring  []model.ExpiryReminder
+	reminders map[model.ExpiryReminder]bool
+
+	chats       map[int]model.ChatInfo
+	members     map[[2]int]string
+	subscribers map[[2]int]bool
+	trials      map[[2]int]bool
+	verified    map[int]bool
+	invoices    map[string]model.Invoice
+	payments    map[int64]model.Payment
+	refunds     []model.Refund
+	tokens      []model.InviteToken
+	payouts     []model.Payout
+	catalog     []model.CatalogChat
+	// promoCodes of any chat by code, promoUses counts redemptions by user
+	// and lockedPromoCodes lists codes read by LockPromoCode.
+	promoCodes       map[string]model.PromoCode
+	promoUses        map[int]int
+	lockedPromoCodes []string
+
+	// analytics is returned by GetAnalytics, analyticsReq is its last request.
+	analytics    model.Analytics
+	analyticsReq model.AnalyticsRequest
+	lastId       int64
+
+	// completeRefundErr fails the next CompleteRefund.
+	completeRefundErr error
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		reminders:   make(map[model.ExpiryReminder]bool),
+		chats:       make(map[int]model.ChatInfo),
+		members:     make(map[[2]int]string),
+		subscribers: make(map[[2]int]bool),
+		trials:      make(map[[2]int]bool),
+		verified:    make(map[int]bool),
+		invoices:    make(map[string]model.Invoice),
+		payments:    make(map[int64]model.Payment),
+		promoCodes:  make(map[string]model.PromoCode),
+		promoUses:   make(map[int]int),
+	}
+}
+
+// nextId must be called with mu held.
+func (f *fakeRepo) nextId() int64 {
+	f.lastId++
+
+	return f.lastId
+}
+
+func (f *fakeRepo) Atomic(ctx context.Context, fn func(context.Context, repo.Repo) error) error {
+	return fn(ctx, f)
+}
+
+func (f *fakeRepo) GetExpiringSubscriptions(_ context.Context, _ time.Time, _ time.Duration, to time.Duration, limit int) ([]model.ExpiryReminder, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var res []model.ExpiryReminder
+
+	for _, r := range f.expiring {
+		if r.Window == to && !f.reminders[r] && len(res) < limit {
+			res = append(res, r)
+		}
+	}
+
+	return res, nil
+}
+
+func (f *fakeRepo) AddReminder(_ context.Context, r model.ExpiryReminder) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.reminders[r] {
+		return false, nil
+	}
+
+	f.reminders[r] = true
+
+	return true, nil
+}
+
+func (f *fakeRepo) DeleteReminder(_ context.Context, r model.ExpiryReminder) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.reminders, r)
+
+	return nil
+}
+
+func (f *fakeRepo) GetChat(_ context.Context, chat_id int) (model.ChatInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	chat, ok := f.chats[chat_id]
+	if !ok {
+		return model.ChatInfo{}, fmt.Errorf("chat %d. %w", chat_id, model.ErrNotFound)
+	}
+
+	return chat, nil
+}
+
+// AddNewChat reuses deleted chats like the pg repo, ClearDeletedChat is
+// expected to have run.
+func (f *fakeRepo) AddNewChat(_ context.Context, chat model.AddNewChat) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if c, ok := f.chats[chat.ChatId]; ok && c.Status != model.ChatDeleted {
+		return fmt.Errorf("chat %d already exists. %w", chat.ChatId, model.ErrConflict)
+	}
+
+	c := model.ChatInfo{
+		ChatId:        chat.ChatId,
+		Name:          chat.Name,
+		TrialDays:     chat.TrialDays,
+		ContentRating: model.RatingGeneral,
+		Status:        model.ChatActive,
+		IsActive:      true,
+	}
+	c.SetPrice(model.NewMoney(chat.Amount(), chat.Currency))
+
+	f.chats[chat.ChatId] = c
+	f.members[[2]int{chat.ChatId, chat.OwnerId}] = model.RoleOwner
+
+	return nil
+}
+
+func (f *fakeRepo) ClearDeletedChat(_ context.Context, chat_id int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.chats[chat_id].Status != model.ChatDeleted {
+		return nil
+	}
+
+	for _, m := range []map[[2]int]bool{f.subscribers, f.trials} {
+		for k := range m {
+			if k[0] == chat_id {
+				delete(m, k)
+			}
+		}
+	}
+
+	for k := range f.members {
+		if k[0] == chat_id {
+			delete(f.members, k)
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeRepo) LockChat(ctx context.Context, chat_id int) (model.ChatInfo, error) {
+	return f.GetChat(ctx, chat_id)
+}
+
+func (f *fakeRepo) SetChatStatus(_ context.Context, chat_id int, old_status string, status string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	chat := f.chats[chat_id]
+	if chat.Status != old_status {
+		return fmt.Errorf("chat %d is %s. %w", chat_id, chat.Status, model.ErrConflict)
+	}
+
+	chat.Status = status
+	chat.IsActive = status == model.ChatActive
+	chat.StatusChangedAt = time.Now()
+	f.chats[chat_id] = chat
+
+	return nil
+}
+
+func (f *fakeRepo) ExtendSubscriptions(context.Context, int, time.Time) (int, error) {
+	return 0, nil
+}
+
+func (f *fakeRepo) CountPaidSubscribers(_ context.Context, chat_id int) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	paid := make(map[int]bool)
+
+	for _, p := range f.payments {
+		if p.ChatId == chat_id && p.Refunded.Amount < p.Amount.Amount {
+			paid[p.UserId] = true
+		}
+	}
+
+	return len(paid), nil
+}
+
+// DeleteChat keeps subscriptions in place like the pg repo, which only
+// revokes them.
+func (f *fakeRepo) DeleteChat(_ context.Context, chat_id int) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var n int
+
+	for k := range f.subscribers {
+		if k[0] == chat_id {
+			n++
+		}
+	}
+
+	return n, nil
+}
+
+func (f *fakeRepo) GetChatTrialDays(_ context.Context, chat_id int) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.chats[chat_id].TrialDays, nil
+}
+
+func (f *fakeRepo) StartTrial(_ context.Context, chat_id int, user_id int) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.trials[[2]int{chat_id, user_id}] {
+		return false, nil
+	}
+
+	f.trials[[2]int{chat_id, user_id}] = true
+
+	return true, nil
+}
+
+func (f *fakeRepo) NewTrialSubscribe(_ context.Context, chat_id int, user_id int, _ time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.subscribers[[2]int{chat_id, user_id}] = true
+
+	return nil
+}
+
+func (f *fakeRepo) GetChatMemberRole(_ context.Context, chat_id int, user_id int) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	role, ok := f.members[[2]int{chat_id, user_id}]
+	if !ok {
+		return "", fmt.Errorf("member %d of chat %d. %w", user_id, chat_id, model.ErrNotFound)
+	}
+
+	return role, nil
+}
+
+func (f *fakeRepo) LockChatShared(ctx context.Context, chat_id int) (model.ChatInfo, error) {
+	return f.GetChat(ctx, chat_id)
+}
+
+func (f *fakeRepo) GetChatPrice(ctx context.Context, chat_id int) (model.Money, error) {
+	chat, err := f.GetChat(ctx, chat_id)
+
+	return chat.Price, err
+}
+
+func (f *fakeRepo) GetLockedPrice(context.Context, int, int) (int, bool, error) {
+	return 0, false, nil
+}
+
+func (f *fakeRepo) IsAgeVerified(_ context.Context, user_id int) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.verified[user_id], nil
+}
+
+func (f *fakeRepo) IsSubscribeExists(_ context.Context, chat_id int, user_id int) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.subscribers[[2]int{chat_id, user_id}], nil
+}
+
+func (f *fakeRepo) NewSubscribe(_ context.Context, chat_id int, user_id int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.subscribers[[2]int{chat_id, user_id}] = true
+
+	return nil
+}
+
+func (f *fakeRepo) AddInvoice(_ context.Context, invoice model.Invoice) (model.Invoice, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	invoice.Id = f.nextId()
+	invoice.Status = model.InvoicePending
+	invoice.CreatedAt = time.Now()
+	f.invoices[invoice.Payload] = invoice
+
+	return invoice, nil
+}
+
+func (f *fakeRepo) GetInvoice(_ context.Context, payload string) (model.Invoice, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	invoice, ok := f.invoices[payload]
+	if !ok {
+		return model.Invoice{}, fmt.Errorf("invoice. %w", model.ErrNotFound)
+	}
+
+	return invoice, nil
+}
+
+func (f *fakeRepo) MarkInvoicePaid(_ context.Context, invoice_id int64, payment_id int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for payload, invoice := range f.invoices {
+		if invoice.Id == invoice_id {
+			invoice.Status = model.InvoicePaid
+			invoice.PaymentId = payment_id
+			f.invoices[payload] = invoice
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeRepo) Pay(_ context.Context, payment model.Payment) (model.Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payment.Id = f.nextId()
+	payment.Refunded = model.NewMoney(0, payment.Amount.Currency)
+	payment.CreatedAt = time.Now()
+	f.payments[payment.Id] = payment
+
+	return payment, nil
+}
+
+func (f *fakeRepo) GetPayment(_ context.Context, chat_id int, payment_id int64) (model.Payment, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payment, ok := f.payments[payment_id]
+	if !ok || payment.ChatId != chat_id {
+		return model.Payment{}, fmt.Errorf("payment %d. %w", payment_id, model.ErrNotFound)
+	}
+
+	return payment, nil
+}
+
+func (f *fakeRepo) AddInviteToken(_ context.Context, token model.InviteToken) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.tokens = append(f.tokens, token)
+
+	return nil
+}
+
+func (f *fakeRepo) AddRefund(_ context.Context, refund model.Refund, _ float64) (model.Refund, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.applyRefund(refund); err != nil {
+		return model.Refund{}, err
+	}
+
+	refund.Id = f.nextId()
+	refund.Status = model.RefundCompleted
+	refund.CreatedAt = time.Now()
+	f.refunds = append(f.refunds, refund)
+
+	return refund, nil
+}
+
+func (f *fakeRepo) AddPendingRefund(_ context.Context, refund model.Refund) (model.Refund, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, r := range f.refunds {
+		if r.PaymentId == refund.PaymentId && r.Status == model.RefundPending {
+			return model.Refund{}, fmt.Errorf("payment %d has a pending refund. %w", refund.PaymentId, model.ErrConflict)
+		}
+	}
+
+	refund.Id = f.nextId()
+	refund.Status = model.RefundPending
+	refund.CreatedAt = time.Now()
+	f.refunds = append(f.refunds, refund)
+
+	return refund, nil
+}
+
+func (f *fakeRepo) CompleteRefund(_ context.Context, refund model.Refund, _ float64) (model.Refund, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.completeRefundErr; err != nil {
+		f.completeRefundErr = nil
+
+		return model.Refund{}, err
+	}
+
+	if !slices.ContainsFunc(f.refunds, func(r model.Refund) bool { return r.Id == refund.Id && r.Status == model.RefundPending }) {
+		return model.Refund{}, fmt.Errorf("pending refund %d. %w", refund.Id, model.ErrNotFound)
+	}
+
+	if err := f.applyRefund(refund); err != nil {
+		return model.Refund{}, err
+	}
+
+	refund.Status = model.RefundCompleted
+	f.setRefund(refund)
+
+	return refund, nil
+}
+
+func (f *fakeRepo) FailRefund(_ context.Context, refund_id int64, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, r := range f.refunds {
+		if r.Id == refund_id {
+			f.refunds[i].Status = model.RefundFailed
+			f.refunds[i].Error = reason
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeRepo) GetRefund(_ context.Context, refund_id int64) (model.Refund, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, r := range f.refunds {
+		if r.Id == refund_id {
+			return r, nil
+		}
+	}
+
+	return model.Refund{}, fmt.Errorf("refund %d. %w", refund_id, model.ErrNotFound)
+}
+
+// applyRefund must be called with mu held.
+func (f *fakeRepo) applyRefund(refund model.Refund) error {
+	payment := f.payments[refund.PaymentId]
+
+	if payment.Refunded.Amount+refund.Amount.Amount > payment.Amount.Amount {
+		return fmt.Errorf("refund exceeds payment %d. %w", refund.PaymentId, model.ErrConflict)
+	}
+
+	payment.Refunded.Amount += refund.Amount.Amount
+	f.payments[payment.Id] = payment
+
+	return nil
+}
+
+// setRefund must be called with mu held.
+func (f *fakeRepo) setRefund(refund model.Refund) {
+	for i, r := range f.refunds {
+		if r.Id == refund.Id {
+			f.refunds[i] = refund
+		}
+	}
+}
+
+func (f *fakeRepo) GetEvents(context.Context, model.EventCursor, model.EventFilter, int) ([]model.Event, error) {
+	return nil, nil
+}
+
+func (f *fakeRepo) ExportPayments(context.Context, model.PaymentsExportRequest, func(model.Payment) error) error {
+	return nil
+}
+
+func (f *fakeRepo) LockOwnerPayouts(context.Context, int) error {
+	return nil
+}
+
+// GetBalanceTotals sums every payment and payout, they all belong to one
+// owner in tests.
+func (f *fakeRepo) GetBalanceTotals(_ context.Context, _ int, cleared_before time.Time) ([]model.BalanceTotals, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	totals := make(map[string]*model.BalanceTotals)
+
+	get := func(currency string) *model.BalanceTotals {
+		if totals[currency] == nil {
+			totals[currency] = &model.BalanceTotals{Currency: currency}
+		}
+
+		return totals[currency]
+	}
+
+	for _, p := range f.payments {
+		t := get(p.Amount.Currency)
+		net := p.Amount.Amount - p.Refunded.Amount
+		commission := net * int64(p.CommissionBasisPoints) / maxCommissionBasisPoints
+
+		t.Gross += p.Amount.Amount
+		t.Refunded += p.Refunded.Amount
+
+		if p.CreatedAt.After(cleared_before) {
+			t.Held += net
+			t.HeldCommission += commission
+		} else {
+			t.Cleared += net
+			t.ClearedCommission += commission
+		}
+	}
+
+	for _, p := range f.payouts {
+		t := get(p.Amount.Currency)
+
+		switch p.Status {
+		case model.PayoutPaid:
+			t.PayoutsPaid += p.Amount.Amount
+		case model.PayoutRequested, model.PayoutProcessing:
+			t.PayoutsOutstanding += p.Amount.Amount
+		}
+	}
+
+	res := make([]model.BalanceTotals, 0, len(totals))
+
+	for _, t := range totals {
+		res = append(res, *t)
+	}
+
+	slices.SortFunc(res, func(a, b model.BalanceTotals) int {
+		return strings.Compare(a.Currency, b.Currency)
+	})
+
+	return res, nil
+}
+
+func (f *fakeRepo) AddPayout(_ context.Context, payout model.Payout) (model.Payout, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	payout.Id = f.nextId()
+	payout.Status = model.PayoutRequested
+
+	f.payouts = append(f.payouts, payout)
+
+	return payout, nil
+}
+
+// GetCatalog pages catalog sorted by SortKey, other filters are ignored.
+func (f *fakeRepo) GetCatalog(_ context.Context, query model.CatalogQuery) ([]model.CatalogChat, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	chats := slices.Clone(f.catalog)
+
+	slices.SortFunc(chats, func(a, b model.CatalogChat) int {
+		if a.SortKey != b.SortKey {
+			return cmp.Compare(a.SortKey, b.SortKey)
+		}
+
+		return cmp.Compare(a.ChatId, b.ChatId)
+	})
+
+	var res []model.CatalogChat
+
+	for _, c := range chats {
+		if slices.Contains(query.HiddenRatings, c.ContentRating) {
+			continue
+		}
+
+		after := query.After
+		if after != nil && (c.SortKey < after.SortKey || c.SortKey == after.SortKey && c.ChatId <= after.ChatId) {
+			continue
+		}
+
+		if len(res) < query.Limit {
+			res = append(res, c)
+		}
+	}
+
+	return res, nil
+}
+
+func (f *fakeRepo) GetPromoCode(_ context.Context, _ int, code string) (model.PromoCode, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	promo, ok := f.promoCodes[code]
+	if !ok {
+		return model.PromoCode{}, fmt.Errorf("promo code %s. %w", code, model.ErrNotFound)
+	}
+
+	return promo, nil
+}
+
+func (f *fakeRepo) LockPromoCode(ctx context.Context, chat_id int, code string) (model.PromoCode, error) {
+	promo, err := f.GetPromoCode(ctx, chat_id, code)
+	if err != nil {
+		return model.PromoCode{}, err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lockedPromoCodes = append(f.lockedPromoCodes, code)
+
+	return promo, nil
+}
+
+func (f *fakeRepo) CountPromoRedemptions(_ context.Context, _ int, _ string, user_id int) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.promoUses[user_id], nil
+}
+
+func (f *fakeRepo) CountPayments(_ context.Context, chat_id int, user_id int) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	count := 0
+
+	for _, p := range f.payments {
+		if p.ChatId == chat_id && p.UserId == user_id {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (f *fakeRepo) GetAnalytics(_ context.Context, req model.AnalyticsRequest) (model.Analytics, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.analyticsReq = req
+
+	return f.analytics, nil
+}
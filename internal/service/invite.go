@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"project/internal/logger"
+	"project/internal/model"
+	"project/internal/repo"
+	"time"
+)
+
+const (
+	inviteTokenBytes = 24
+	defaultInviteTTL = 7 * 24 * time.Hour
+	inviteLinkTTL    = time.Hour
+)
+
+func (s *service) newInviteToken(chat_id int, user_id int) (model.InviteToken, error) {
+	b := make([]byte, inviteTokenBytes)
+
+	if _, err := rand.Read(b); err != nil {
+		return model.InviteToken{}, fmt.Errorf("failed to generate invite token. %w", err)
+	}
+
+	return model.InviteToken{
+		Token:     base64.RawURLEncoding.EncodeToString(b),
+		ChatId:    chat_id,
+		UserId:    user_id,
+		ExpiresAt: time.Now().Add(s.inviteTTL),
+	}, nil
+}
+
+// GetInviteToken returns the current invite token of a paid subscription,
+// issuing a new one if the previous was redeemed or expired.
+func (s *service) GetInviteToken(ctx context.Context, chat_id int, user_id int) (model.InviteToken, error) {
+	var token model.InviteToken
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		var err error
+
+		token, err = r.GetActiveInviteToken(ctx, chat_id, user_id)
+		if err == nil {
+			return nil
+		}
+
+		if !errors.Is(err, model.ErrNotFound) {
+			return fmt.Errorf("failed to get invite token in repo. %w", err)
+		}
+
+		paid, err := r.IsPaid(ctx, chat_id, user_id)
+		if err != nil {
+			return fmt.Errorf("failed to check paid status in repo. %w", err)
+		}
+
+		if !paid {
+			return fmt.Errorf("subscription is not paid. %w", model.ErrForbidden)
+		}
+
+		token, err = s.newInviteToken(chat_id, user_id)
+		if err != nil {
+			return err
+		}
+
+		if err := r.AddInviteToken(ctx, token); err != nil {
+			return fmt.Errorf("failed to add invite token in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.InviteToken{}, fmt.Errorf("failed to get invite token. %w", err)
+	}
+
+	return token, nil
+}
+
+// RedeemInvite marks token used by user. Only the subscriber the token was
+// issued to can redeem it, once, while the subscription is paid.
+func (s *service) RedeemInvite(ctx context.Context, token string, user_id int) (model.InviteRedemption, error) {
+	var res model.InviteRedemption
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		t, err := r.GetInviteToken(ctx, token)
+		if err != nil {
+			return fmt.Errorf("failed to get invite token in repo. %w", err)
+		}
+
+		if t.UserId != user_id {
+			return fmt.Errorf("invite token belongs to another user. %w", model.ErrForbidden)
+		}
+
+		if t.RedeemedAt != nil {
+			return fmt.Errorf("invite token already redeemed. %w", model.ErrConflict)
+		}
+
+		if time.Now().After(t.ExpiresAt) {
+			return fmt.Errorf("invite token expired. %w", model.ErrConflict)
+		}
+
+		paid, err := r.IsPaid(ctx, t.ChatId, user_id)
+		if err != nil {
+			return fmt.Errorf("failed to check paid status in repo. %w", err)
+		}
+
+		if !paid {
+			return fmt.Errorf("subscription is not paid. %w", model.ErrForbidden)
+		}
+
+		if err := r.RedeemInviteToken(ctx, token, user_id); err != nil {
+			return fmt.Errorf("failed to redeem invite token in repo. %w", err)
+		}
+
+		res = model.InviteRedemption{ChatId: t.ChatId}
+
+		return nil
+	})
+	if err != nil {
+		return model.InviteRedemption{}, fmt.Errorf("failed to redeem invite. %w", err)
+	}
+
+	if s.tg == nil {
+		return res, nil
+	}
+
+	// Link is created after commit. If it fails the token is released, so
+	// the user can redeem it again.
+	res.InviteLink, err = s.tg.CreateInviteLink(ctx, res.ChatId, fmt.Sprintf("user %d", user_id), time.Now().Add(inviteLinkTTL))
+	if err != nil {
+		if err := s.repo.ReleaseInviteToken(ctx, token, user_id); err != nil {
+			logger.GetLogger().Err(err).Int("chat_id", res.ChatId).Int("user_id", user_id).Msg("failed to release invite token")
+		}
+
+		return model.InviteRedemption{}, fmt.Errorf("failed to create invite link. %w", err)
+	}
+
+	return res, nil
+}
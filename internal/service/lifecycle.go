@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project/internal/model"
+	"project/internal/repo"
+	"slices"
+)
+
+// ChangeChatStatus moves chat along model.ChatTransitions. Leaving the paused
+// status extends subscriptions by the pause, so they lose no paid time.
+// Deleting a chat with paid subscribers needs req.Force and revokes their
+// access without refunds. Admins change the status, only the owner deletes
+// the chat.
+func (s *service) ChangeChatStatus(ctx context.Context, req model.ChangeChatStatus) (model.ChatStatusChange, error) {
+	if _, ok := model.ChatTransitions[req.Status]; !ok && req.Status != model.ChatDeleted {
+		return model.ChatStatusChange{}, fmt.Errorf("unknown chat status %q. %w", req.Status, model.ErrInvalidArgument)
+	}
+
+	change := model.ChatStatusChange{
+		ChatId: req.ChatId,
+		Status: req.Status,
+	}
+
+	required := model.RoleAdmin
+	if req.Status == model.ChatDeleted || req.Force {
+		required = model.RoleOwner
+	}
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		if err := authorize(ctx, r, req.ChatId, req.ActorId, required); err != nil {
+			return err
+		}
+
+		chat, err := r.LockChat(ctx, req.ChatId)
+		if err != nil {
+			return fmt.Errorf("failed to lock chat in repo. %w", err)
+		}
+
+		change.OldStatus = chat.Status
+
+		if !slices.Contains(model.ChatTransitions[chat.Status], req.Status) {
+			return fmt.Errorf("chat cannot move from %s to %s. %w", chat.Status, req.Status, model.ErrConflict)
+		}
+
+		if chat.Status == model.ChatPaused && req.Status != model.ChatDeleted {
+			change.Extended, err = r.ExtendSubscriptions(ctx, chat.ChatId, chat.StatusChangedAt)
+			if err != nil {
+				return fmt.Errorf("failed to extend subscriptions in repo. %w", err)
+			}
+		}
+
+		if req.Status == model.ChatDeleted {
+			if !req.Force {
+				paid, err := r.CountPaidSubscribers(ctx, chat.ChatId)
+				if err != nil {
+					return fmt.Errorf("failed to count paid subscribers in repo. %w", err)
+				}
+
+				if paid > 0 {
+					return fmt.Errorf("chat has %d paid subscribers, refund them or force deletion. %w", paid, model.ErrConflict)
+				}
+			}
+
+			change.Revoked, err = r.DeleteChat(ctx, chat.ChatId)
+			if err != nil {
+				return fmt.Errorf("failed to delete chat in repo. %w", err)
+			}
+		}
+
+		if err := r.SetChatStatus(ctx, chat.ChatId, chat.Status, req.Status); err != nil {
+			return fmt.Errorf("failed to set chat status in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.ChatStatusChange{}, fmt.Errorf("failed to change chat status. %w", err)
+	}
+
+	return change, nil
+}
+
+// DisableChat archives chat, letting its subscriptions run out.
+func (s *service) DisableChat(ctx context.Context, req model.ChatActor) error {
+	_, err := s.ChangeChatStatus(ctx, model.ChangeChatStatus{
+		ChatId:  req.ChatId,
+		ActorId: req.ActorId,
+		Status:  model.ChatArchived,
+	})
+
+	return err
+}
+
+// requireActiveChat returns chat or ErrConflict unless it takes new
+// subscribers and payments. The chat is locked shared, so its status cannot
+// change before the unit of work r belongs to commits.
+func requireActiveChat(ctx context.Context, r repo.Repo, chat_id int) (model.ChatInfo, error) {
+	chat, err := r.LockChatShared(ctx, chat_id)
+	if err != nil {
+		return model.ChatInfo{}, fmt.Errorf("failed to lock chat in repo. %w", err)
+	}
+
+	if chat.Status != model.ChatActive {
+		return model.ChatInfo{}, fmt.Errorf("chat is %s. %w", chat.Status, model.ErrConflict)
+	}
+
+	return chat, nil
+}
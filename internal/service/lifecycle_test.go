@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"project/internal/model"
+)
+
+const (
+	lifecycleChatId = -200
+	ownerId         = 10
+	adminId         = 11
+	subscriberId    = 12
+)
+
+func newLifecycleService(t *testing.T) (*service, *fakeRepo) {
+	t.Helper()
+
+	r := newFakeRepo()
+	s := &service{repo: r}
+
+	err := s.AddNewChat(context.Background(), model.AddNewChat{ChatId: lifecycleChatId, OwnerId: ownerId, Price: 500, TrialDays: 7})
+	if err != nil {
+		t.Fatalf("AddNewChat() error = %v", err)
+	}
+
+	r.members[[2]int{lifecycleChatId, adminId}] = model.RoleAdmin
+
+	return s, r
+}
+
+func TestChangeChatStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		actorId int
+		status  string
+		want    error
+	}{
+		{name: "admin pauses", actorId: adminId, status: model.ChatPaused},
+		{name: "admin archives", actorId: adminId, status: model.ChatArchived},
+		{name: "subscriber pauses", actorId: subscriberId, status: model.ChatPaused, want: model.ErrForbidden},
+		{name: "admin deletes", actorId: adminId, status: model.ChatDeleted, want: model.ErrForbidden},
+		{name: "owner deletes", actorId: ownerId, status: model.ChatDeleted},
+		{name: "unknown status", actorId: ownerId, status: "frozen", want: model.ErrInvalidArgument},
+		{name: "same status", actorId: ownerId, status: model.ChatActive, want: model.ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, r := newLifecycleService(t)
+
+			change, err := s.ChangeChatStatus(context.Background(), model.ChangeChatStatus{
+				ChatId:  lifecycleChatId,
+				ActorId: tt.actorId,
+				Status:  tt.status,
+			})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("ChangeChatStatus() error = %v, want %v", err, tt.want)
+			}
+
+			if tt.want != nil {
+				return
+			}
+
+			if change.OldStatus != model.ChatActive || r.chats[lifecycleChatId].Status != tt.status {
+				t.Fatalf("change = %+v, chat status %s, want %s", change, r.chats[lifecycleChatId].Status, tt.status)
+			}
+		})
+	}
+}
+
+func TestDeleteChatWithPaidSubscribers(t *testing.T) {
+	s, r := newLifecycleService(t)
+
+	r.payments[1] = model.Payment{Id: 1, ChatId: lifecycleChatId, UserId: subscriberId, Amount: model.NewMoney(50000, model.CurrencyRUB)}
+
+	req := model.ChangeChatStatus{ChatId: lifecycleChatId, ActorId: ownerId, Status: model.ChatDeleted}
+
+	if _, err := s.ChangeChatStatus(context.Background(), req); !errors.Is(err, model.ErrConflict) {
+		t.Fatalf("ChangeChatStatus() error = %v, want %v", err, model.ErrConflict)
+	}
+
+	req.Force = true
+
+	if _, err := s.ChangeChatStatus(context.Background(), req); err != nil {
+		t.Fatalf("forced ChangeChatStatus() error = %v", err)
+	}
+}
+
+func TestReAddDeletedChat(t *testing.T) {
+	s, r := newLifecycleService(t)
+	ctx := context.Background()
+
+	if err := s.NewSubscribe(ctx, lifecycleChatId, subscriberId); err != nil {
+		t.Fatalf("NewSubscribe() error = %v", err)
+	}
+
+	chat := model.AddNewChat{ChatId: lifecycleChatId, OwnerId: 20, Price: 300, TrialDays: 3}
+
+	if err := s.AddNewChat(ctx, chat); !errors.Is(err, model.ErrConflict) {
+		t.Fatalf("AddNewChat() of active chat error = %v, want %v", err, model.ErrConflict)
+	}
+
+	_, err := s.ChangeChatStatus(ctx, model.ChangeChatStatus{ChatId: lifecycleChatId, ActorId: ownerId, Status: model.ChatDeleted})
+	if err != nil {
+		t.Fatalf("ChangeChatStatus() error = %v", err)
+	}
+
+	if err := s.AddNewChat(ctx, chat); err != nil {
+		t.Fatalf("AddNewChat() of deleted chat error = %v", err)
+	}
+
+	for _, id := range []int{ownerId, adminId} {
+		if role, ok := r.members[[2]int{lifecycleChatId, id}]; ok {
+			t.Fatalf("previous member %d kept role %s", id, role)
+		}
+	}
+
+	if r.members[[2]int{lifecycleChatId, 20}] != model.RoleOwner {
+		t.Fatalf("members = %v, want new owner", r.members)
+	}
+
+	if got := r.chats[lifecycleChatId]; got.Status != model.ChatActive || got.Price.Amount != 30000 {
+		t.Fatalf("chat = %+v, want active at 30000", got)
+	}
+
+	// The old subscription and trial are gone, so the user subscribes and
+	// gets the trial of the new chat.
+	if err := s.NewSubscribe(ctx, lifecycleChatId, subscriberId); err != nil {
+		t.Fatalf("NewSubscribe() after re-add error = %v", err)
+	}
+
+	if !r.trials[[2]int{lifecycleChatId, subscriberId}] {
+		t.Fatal("trial not started after re-add")
+	}
+}
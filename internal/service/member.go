@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"project/internal/model"
+	"project/internal/repo"
+)
+
+// authorize returns ErrForbidden unless actor is a member of chat with at
+// least the required role.
+func authorize(ctx context.Context, r repo.Repo, chat_id int, actor_id int, required string) error {
+	role, err := r.GetChatMemberRole(ctx, chat_id, actor_id)
+	if errors.Is(err, model.ErrNotFound) {
+		return fmt.Errorf("user %d is not a member of chat %d. %w", actor_id, chat_id, model.ErrForbidden)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to get member role in repo. %w", err)
+	}
+
+	if !model.HasRole(role, required) {
+		return fmt.Errorf("%s role is required. %w", required, model.ErrForbidden)
+	}
+
+	return nil
+}
+
+// AddChatMember adds admin or viewer to chat or changes role of a member.
+// Only the owner manages members, ownership itself moves by a transfer.
+func (s *service) AddChatMember(ctx context.Context, req model.AddChatMember) (model.ChatMember, error) {
+	if req.Role != model.RoleAdmin && req.Role != model.RoleViewer {
+		return model.ChatMember{}, fmt.Errorf("role must be %s or %s. %w", model.RoleAdmin, model.RoleViewer, model.ErrInvalidArgument)
+	}
+
+	if req.UserId <= 0 {
+		return model.ChatMember{}, fmt.Errorf("user_id is required. %w", model.ErrInvalidArgument)
+	}
+
+	var member model.ChatMember
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		if err := authorize(ctx, r, req.ChatId, req.ActorId, model.RoleOwner); err != nil {
+			return err
+		}
+
+		var err error
+
+		member, err = r.AddChatMember(ctx, model.ChatMember{
+			ChatId:  req.ChatId,
+			UserId:  req.UserId,
+			Role:    req.Role,
+			AddedBy: req.ActorId,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add member in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.ChatMember{}, fmt.Errorf("failed to add chat member. %w", err)
+	}
+
+	return member, nil
+}
+
+func (s *service) GetChatMembers(ctx context.Context, req model.ChatActor) ([]model.ChatMember, error) {
+	if err := authorize(ctx, s.repo, req.ChatId, req.ActorId, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.GetChatMembers(ctx, req.ChatId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get members in repo. %w", err)
+	}
+
+	return members, nil
+}
+
+// RemoveChatMember removes member from chat. Members can leave on their own,
+// others are removed by the owner.
+func (s *service) RemoveChatMember(ctx context.Context, req model.RemoveChatMember) error {
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		if req.ActorId != req.UserId {
+			if err := authorize(ctx, r, req.ChatId, req.ActorId, model.RoleOwner); err != nil {
+				return err
+			}
+		}
+
+		if err := r.RemoveChatMember(ctx, req.ChatId, req.UserId); err != nil {
+			return fmt.Errorf("failed to remove member in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to remove chat member. %w", err)
+	}
+
+	return nil
+}
+
+// InitiateTransfer offers chat to a new owner, replacing the pending offer if
+// there is one. Nothing changes until the new owner accepts it.
+func (s *service) InitiateTransfer(ctx context.Context, req model.InitiateTransfer) (model.ChatTransfer, error) {
+	if req.NewOwnerId <= 0 || req.NewOwnerId == req.ActorId {
+		return model.ChatTransfer{}, fmt.Errorf("new_owner_id must be another user. %w", model.ErrInvalidArgument)
+	}
+
+	var transfer model.ChatTransfer
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		chat, err := r.LockChat(ctx, req.ChatId)
+		if err != nil {
+			return fmt.Errorf("failed to lock chat in repo. %w", err)
+		}
+
+		if chat.Status == model.ChatDeleted {
+			return fmt.Errorf("chat is deleted. %w", model.ErrConflict)
+		}
+
+		if err := authorize(ctx, r, req.ChatId, req.ActorId, model.RoleOwner); err != nil {
+			return err
+		}
+
+		transfer, err = r.AddTransfer(ctx, model.ChatTransfer{
+			ChatId:      req.ChatId,
+			FromOwnerId: req.ActorId,
+			ToOwnerId:   req.NewOwnerId,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add transfer in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.ChatTransfer{}, fmt.Errorf("failed to initiate transfer. %w", err)
+	}
+
+	return transfer, nil
+}
+
+// AcceptTransfer makes the recipient of the pending transfer the chat owner.
+// Payments made before stay in the balance of the previous owner.
+func (s *service) AcceptTransfer(ctx context.Context, req model.ChatActor) (model.ChatTransfer, error) {
+	var transfer model.ChatTransfer
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		chat, err := r.LockChat(ctx, req.ChatId)
+		if err != nil {
+			return fmt.Errorf("failed to lock chat in repo. %w", err)
+		}
+
+		if chat.Status == model.ChatDeleted {
+			return fmt.Errorf("chat is deleted. %w", model.ErrConflict)
+		}
+
+		transfer, err = r.GetPendingTransfer(ctx, req.ChatId)
+		if err != nil {
+			return fmt.Errorf("failed to get transfer in repo. %w", err)
+		}
+
+		if transfer.ToOwnerId != req.ActorId {
+			return fmt.Errorf("transfer is offered to another user. %w", model.ErrForbidden)
+		}
+
+		if err := authorize(ctx, r, req.ChatId, transfer.FromOwnerId, model.RoleOwner); err != nil {
+			return fmt.Errorf("transfer was initiated by a previous owner. %w", model.ErrConflict)
+		}
+
+		transfer.Status = model.TransferAccepted
+
+		transfer, err = r.ResolveTransfer(ctx, transfer)
+		if err != nil {
+			return fmt.Errorf("failed to resolve transfer in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.ChatTransfer{}, fmt.Errorf("failed to accept transfer. %w", err)
+	}
+
+	return transfer, nil
+}
+
+// CancelTransfer cancels the pending transfer of chat when called by the
+// owner and declines it when called by its recipient.
+func (s *service) CancelTransfer(ctx context.Context, req model.ChatActor) (model.ChatTransfer, error) {
+	var transfer model.ChatTransfer
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		var err error
+
+		transfer, err = r.GetPendingTransfer(ctx, req.ChatId)
+		if err != nil {
+			return fmt.Errorf("failed to get transfer in repo. %w", err)
+		}
+
+		switch req.ActorId {
+		case transfer.FromOwnerId:
+			transfer.Status = model.TransferCancelled
+		case transfer.ToOwnerId:
+			transfer.Status = model.TransferDeclined
+		default:
+			return fmt.Errorf("transfer belongs to other users. %w", model.ErrForbidden)
+		}
+
+		transfer, err = r.ResolveTransfer(ctx, transfer)
+		if err != nil {
+			return fmt.Errorf("failed to resolve transfer in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.ChatTransfer{}, fmt.Errorf("failed to cancel transfer. %w", err)
+	}
+
+	return transfer, nil
+}
+
+func (s *service) GetPendingTransfers(ctx context.Context, user_id int) ([]model.ChatTransfer, error) {
+	transfers, err := s.repo.GetPendingTransfers(ctx, user_id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending transfers in repo. %w", err)
+	}
+
+	return transfers, nil
+}
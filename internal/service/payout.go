@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project/internal/model"
+	"project/internal/repo"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	maxCommissionBasisPoints = 10000
+
+	defaultStatementPeriod = 30 * 24 * time.Hour
+	maxStatementPeriod     = 366 * 24 * time.Hour
+
+	maxPayoutReferenceLength = 200
+)
+
+func balance(t model.BalanceTotals) model.Balance {
+	money := func(amount int64) model.Money {
+		return model.NewMoney(amount, t.Currency)
+	}
+
+	cleared := t.Cleared - t.ClearedCommission
+
+	return model.Balance{
+		Currency:   t.Currency,
+		Gross:      money(t.Gross),
+		Refunded:   money(t.Refunded),
+		Commission: money(t.ClearedCommission + t.HeldCommission),
+		OnHold:     money(t.Held - t.HeldCommission),
+		InPayout:   money(t.PayoutsOutstanding),
+		PaidOut:    money(t.PayoutsPaid),
+		Available:  money(cleared - t.PayoutsPaid - t.PayoutsOutstanding),
+	}
+}
+
+func (s *service) getBalances(ctx context.Context, r repo.Repo, owner_id int) ([]model.Balance, error) {
+	totals, err := r.GetBalanceTotals(ctx, owner_id, time.Now().Add(-s.payoutHold))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance totals in repo. %w", err)
+	}
+
+	res := make([]model.Balance, 0, len(totals))
+
+	for _, t := range totals {
+		res = append(res, balance(t))
+	}
+
+	return res, nil
+}
+
+// GetBalances returns owner balance in every currency the owner was paid in.
+func (s *service) GetBalances(ctx context.Context, owner_id int) ([]model.Balance, error) {
+	return s.getBalances(ctx, s.repo, owner_id)
+}
+
+func (s *service) GetStatement(ctx context.Context, req model.StatementRequest) (model.Statement, error) {
+	if req.To.IsZero() {
+		req.To = time.Now()
+	}
+
+	if req.From.IsZero() {
+		req.From = req.To.Add(-defaultStatementPeriod)
+	}
+
+	if !req.To.After(req.From) || req.To.Sub(req.From) > maxStatementPeriod {
+		return model.Statement{}, fmt.Errorf("statement period must be positive and at most %s. %w", maxStatementPeriod, model.ErrInvalidArgument)
+	}
+
+	entries, err := s.repo.GetStatement(ctx, req.OwnerId, req.From, req.To)
+	if err != nil {
+		return model.Statement{}, fmt.Errorf("failed to get statement in repo. %w", err)
+	}
+
+	return model.Statement{
+		OwnerId: req.OwnerId,
+		From:    req.From,
+		To:      req.To,
+		Entries: entries,
+	}, nil
+}
+
+// RequestPayout reserves amount of available balance for payout.
+func (s *service) RequestPayout(ctx context.Context, req model.PayoutRequest) (model.Payout, error) {
+	req.Amount.Currency = model.NormalizeCurrency(req.Amount.Currency)
+
+	if err := model.ValidateCurrency(req.Amount.Currency); err != nil {
+		return model.Payout{}, err
+	}
+
+	if req.Amount.Amount <= 0 {
+		return model.Payout{}, fmt.Errorf("payout amount must be positive. %w", model.ErrInvalidArgument)
+	}
+
+	var payout model.Payout
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		if err := r.LockOwnerPayouts(ctx, req.OwnerId); err != nil {
+			return fmt.Errorf("failed to lock owner payouts in repo. %w", err)
+		}
+
+		balances, err := s.getBalances(ctx, r, req.OwnerId)
+		if err != nil {
+			return err
+		}
+
+		var available int64
+
+		for _, b := range balances {
+			if b.Currency == req.Amount.Currency {
+				available = b.Available.Amount
+			}
+		}
+
+		if req.Amount.Amount > available {
+			return fmt.Errorf("only %d %s minor units are available for payout. %w", max(available, 0), req.Amount.Currency, model.ErrConflict)
+		}
+
+		payout, err = r.AddPayout(ctx, model.Payout{
+			OwnerId: req.OwnerId,
+			Amount:  req.Amount,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add payout in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Payout{}, fmt.Errorf("failed to request payout. %w", err)
+	}
+
+	return payout, nil
+}
+
+func (s *service) GetPayouts(ctx context.Context, owner_id int) ([]model.Payout, error) {
+	payouts, err := s.repo.GetPayouts(ctx, owner_id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payouts in repo. %w", err)
+	}
+
+	return payouts, nil
+}
+
+// UpdatePayoutStatus moves payout along model.PayoutTransitions. Rejected and
+// failed payouts return their amount to the available balance.
+func (s *service) UpdatePayoutStatus(ctx context.Context, upd model.PayoutStatusUpdate) (model.Payout, error) {
+	upd.Reference = strings.TrimSpace(upd.Reference)
+
+	if len(upd.Reference) > maxPayoutReferenceLength {
+		return model.Payout{}, fmt.Errorf("reference must be at most %d characters. %w", maxPayoutReferenceLength, model.ErrInvalidArgument)
+	}
+
+	var payout model.Payout
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		var err error
+
+		payout, err = r.GetPayout(ctx, upd.Id)
+		if err != nil {
+			return fmt.Errorf("failed to get payout in repo. %w", err)
+		}
+
+		if !slices.Contains(model.PayoutTransitions[payout.Status], upd.Status) {
+			return fmt.Errorf("payout cannot move from %s to %q. %w", payout.Status, upd.Status, model.ErrConflict)
+		}
+
+		payout.Status = upd.Status
+
+		if upd.Reference != "" {
+			payout.Reference = upd.Reference
+		}
+
+		payout, err = r.UpdatePayout(ctx, payout)
+		if err != nil {
+			return fmt.Errorf("failed to update payout in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Payout{}, fmt.Errorf("failed to update payout status. %w", err)
+	}
+
+	return payout, nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"project/internal/model"
+)
+
+const payoutHold = 7 * 24 * time.Hour
+
+// newPayoutService returns service with a cleared and a held payment of
+// ownerId and a paid and an outstanding payout.
+func newPayoutService() (*service, *fakeRepo) {
+	r := newFakeRepo()
+	now := time.Now()
+
+	r.payments[1] = model.Payment{
+		Id:                    1,
+		Amount:                model.NewMoney(10000, "RUB"),
+		Refunded:              model.NewMoney(2000, "RUB"),
+		CommissionBasisPoints: 1000,
+		CreatedAt:             now.Add(-payoutHold - time.Hour),
+	}
+	r.payments[2] = model.Payment{
+		Id:                    2,
+		Amount:                model.NewMoney(5005, "RUB"),
+		CommissionBasisPoints: 1000,
+		CreatedAt:             now.Add(-payoutHold + time.Hour),
+	}
+	r.payouts = []model.Payout{
+		{OwnerId: ownerId, Amount: model.NewMoney(3000, "RUB"), Status: model.PayoutPaid},
+		{OwnerId: ownerId, Amount: model.NewMoney(1000, "RUB"), Status: model.PayoutRequested},
+		{OwnerId: ownerId, Amount: model.NewMoney(500, "RUB"), Status: model.PayoutRejected},
+	}
+	r.lastId = 2
+
+	return &service{repo: r, payoutHold: payoutHold}, r
+}
+
+func TestGetBalances(t *testing.T) {
+	s, _ := newPayoutService()
+
+	balances, err := s.GetBalances(context.Background(), ownerId)
+	if err != nil {
+		t.Fatalf("GetBalances() error = %v", err)
+	}
+
+	rub := func(amount int64) model.Money {
+		return model.NewMoney(amount, "RUB")
+	}
+
+	// Commission is 800 of the cleared payment and 500 of the held one,
+	// rounded down from 500.5.
+	want := model.Balance{
+		Currency:   "RUB",
+		Gross:      rub(15005),
+		Refunded:   rub(2000),
+		Commission: rub(1300),
+		OnHold:     rub(4505),
+		InPayout:   rub(1000),
+		PaidOut:    rub(3000),
+		Available:  rub(3200),
+	}
+
+	if len(balances) != 1 || balances[0] != want {
+		t.Fatalf("GetBalances() = %+v, want [%+v]", balances, want)
+	}
+}
+
+func TestGetBalancesAfterHoldingPeriod(t *testing.T) {
+	s, _ := newPayoutService()
+	s.payoutHold = time.Minute
+
+	balances, err := s.GetBalances(context.Background(), ownerId)
+	if err != nil {
+		t.Fatalf("GetBalances() error = %v", err)
+	}
+
+	if len(balances) != 1 || balances[0].OnHold.Amount != 0 || balances[0].Available.Amount != 7705 {
+		t.Fatalf("GetBalances() = %+v, want nothing on hold and 7705 available", balances)
+	}
+}
+
+func TestRequestPayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount model.Money
+		want   error
+	}{
+		{name: "available balance", amount: model.NewMoney(3200, "RUB")},
+		{name: "more than available", amount: model.NewMoney(3201, "RUB"), want: model.ErrConflict},
+		{name: "held money", amount: model.NewMoney(7000, "RUB"), want: model.ErrConflict},
+		{name: "other currency", amount: model.NewMoney(100, "USD"), want: model.ErrConflict},
+		{name: "zero amount", amount: model.NewMoney(0, "RUB"), want: model.ErrInvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, r := newPayoutService()
+
+			payout, err := s.RequestPayout(context.Background(), model.PayoutRequest{OwnerId: ownerId, Amount: tt.amount})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("RequestPayout() error = %v, want %v", err, tt.want)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if payout.Status != model.PayoutRequested || payout.Amount != tt.amount || len(r.payouts) != 4 {
+				t.Fatalf("RequestPayout() = %+v, payouts = %+v", payout, r.payouts)
+			}
+
+			// The requested payout is no longer available.
+			if _, err := s.RequestPayout(context.Background(), model.PayoutRequest{OwnerId: ownerId, Amount: model.NewMoney(1, "RUB")}); !errors.Is(err, model.ErrConflict) {
+				t.Fatalf("second RequestPayout() error = %v, want %v", err, model.ErrConflict)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project/internal/model"
+	"project/internal/repo"
+	"strings"
+)
+
+const maxPlanDurationMonths = 120
+
+func validatePlan(plan *model.Plan) error {
+	plan.Name = strings.TrimSpace(plan.Name)
+
+	if plan.Name == "" {
+		return fmt.Errorf("plan name is required. %w", model.ErrInvalidArgument)
+	}
+
+	if plan.Price.Amount < 0 {
+		return fmt.Errorf("plan price must not be negative. %w", model.ErrInvalidArgument)
+	}
+
+	if plan.Lifetime {
+		if plan.DurationMonths != 0 {
+			return fmt.Errorf("lifetime plan must not have duration. %w", model.ErrInvalidArgument)
+		}
+
+		return nil
+	}
+
+	if plan.DurationMonths <= 0 || plan.DurationMonths > maxPlanDurationMonths {
+		return fmt.Errorf("duration_months must be between 1 and %d. %w", maxPlanDurationMonths, model.ErrInvalidArgument)
+	}
+
+	return nil
+}
+
+// planCurrency defaults plan price currency to the chat currency and rejects
+// plans priced in any other.
+func planCurrency(ctx context.Context, r repo.Repo, plan *model.Plan) error {
+	price, err := r.GetChatPrice(ctx, plan.ChatId)
+	if err != nil {
+		return fmt.Errorf("failed to get chat price in repo. %w", err)
+	}
+
+	plan.Price.Currency = model.NormalizeCurrency(plan.Price.Currency)
+
+	if plan.Price.Currency == "" {
+		plan.Price.Currency = price.Currency
+	}
+
+	if plan.Price.Currency != price.Currency {
+		return fmt.Errorf("chat %d is priced in %s. %w", plan.ChatId, price.Currency, model.ErrInvalidArgument)
+	}
+
+	return nil
+}
+
+func (s *service) AddPlan(ctx context.Context, plan model.Plan) (model.Plan, error) {
+	if err := validatePlan(&plan); err != nil {
+		return model.Plan{}, err
+	}
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		if err := authorize(ctx, r, plan.ChatId, plan.ActorId, model.RoleAdmin); err != nil {
+			return err
+		}
+
+		if err := planCurrency(ctx, r, &plan); err != nil {
+			return err
+		}
+
+		id, err := r.AddPlan(ctx, plan)
+		if err != nil {
+			return fmt.Errorf("failed to add plan in repo. %w", err)
+		}
+
+		plan.Id = id
+
+		return nil
+	})
+	if err != nil {
+		return model.Plan{}, fmt.Errorf("failed to add plan. %w", err)
+	}
+
+	return plan, nil
+}
+
+// GetPlans lists plans of the chat. They are public, subscribers choose
+// among them.
+func (s *service) GetPlans(ctx context.Context, chat_id int) ([]model.Plan, error) {
+	plans, err := s.repo.GetPlans(ctx, chat_id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get plans in repo. %w", err)
+	}
+
+	return plans, nil
+}
+
+func (s *service) UpdatePlan(ctx context.Context, plan model.Plan) error {
+	if err := validatePlan(&plan); err != nil {
+		return err
+	}
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		if err := authorize(ctx, r, plan.ChatId, plan.ActorId, model.RoleAdmin); err != nil {
+			return err
+		}
+
+		if err := planCurrency(ctx, r, &plan); err != nil {
+			return err
+		}
+
+		if err := r.UpdatePlan(ctx, plan); err != nil {
+			return fmt.Errorf("failed to update plan in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update plan. %w", err)
+	}
+
+	return nil
+}
+
+// DeletePlan hides plan from new payments. Subscriptions bought on it keep
+// referencing it.
+func (s *service) DeletePlan(ctx context.Context, req model.PlanId) error {
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		if err := authorize(ctx, r, req.ChatId, req.ActorId, model.RoleAdmin); err != nil {
+			return err
+		}
+
+		if err := r.DeletePlan(ctx, req.Id, req.ChatId); err != nil {
+			return fmt.Errorf("failed to delete plan in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete plan. %w", err)
+	}
+
+	return nil
+}
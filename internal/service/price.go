@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project/internal/model"
+	"project/internal/repo"
+)
+
+func (s *service) ChangePrice(ctx context.Context, change model.ChangePrice) (model.PriceChange, error) {
+	if change.Price < 0 || (change.PriceMinor != nil && *change.PriceMinor < 0) {
+		return model.PriceChange{}, fmt.Errorf("price must not be negative. %w", model.ErrInvalidArgument)
+	}
+
+	change.Currency = model.NormalizeCurrency(change.Currency)
+
+	var res model.PriceChange
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		if err := authorize(ctx, r, change.ChatId, change.ActorId, model.RoleAdmin); err != nil {
+			return err
+		}
+
+		var err error
+
+		res, err = r.ChangePrice(ctx, change)
+
+		return err
+	})
+	if err != nil {
+		return model.PriceChange{}, fmt.Errorf("failed to change price. %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *service) GetPriceHistory(ctx context.Context, req model.ChatActor) ([]model.PriceChange, error) {
+	if err := authorize(ctx, s.repo, req.ChatId, req.ActorId, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	history, err := s.repo.GetPriceHistory(ctx, req.ChatId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price history in repo. %w", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project/internal/model"
+	"project/internal/repo"
+	"strings"
+	"time"
+)
+
+const maxPromoCodeLength = 64
+
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func validatePromoCode(code model.PromoCode) error {
+	if code.Code == "" || len(code.Code) > maxPromoCodeLength {
+		return fmt.Errorf("code must be 1 to %d characters. %w", maxPromoCodeLength, model.ErrInvalidArgument)
+	}
+
+	switch code.DiscountType {
+	case model.DiscountPercent:
+		if code.DiscountValue <= 0 || code.DiscountValue > 100 {
+			return fmt.Errorf("percent discount must be between 1 and 100. %w", model.ErrInvalidArgument)
+		}
+	case model.DiscountFixed:
+		if code.DiscountValue <= 0 {
+			return fmt.Errorf("fixed discount must be positive. %w", model.ErrInvalidArgument)
+		}
+	default:
+		return fmt.Errorf("unknown discount type %q. %w", code.DiscountType, model.ErrInvalidArgument)
+	}
+
+	if code.ValidFrom != nil && code.ValidUntil != nil && !code.ValidUntil.After(*code.ValidFrom) {
+		return fmt.Errorf("valid_until must be after valid_from. %w", model.ErrInvalidArgument)
+	}
+
+	if code.UsageLimit < 0 || code.PerUserLimit < 0 {
+		return fmt.Errorf("limits must not be negative. %w", model.ErrInvalidArgument)
+	}
+
+	return nil
+}
+
+func (s *service) AddPromoCode(ctx context.Context, code model.PromoCode) error {
+	code.Code = normalizePromoCode(code.Code)
+
+	if err := validatePromoCode(code); err != nil {
+		return err
+	}
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		if err := authorize(ctx, r, code.ChatId, code.ActorId, model.RoleAdmin); err != nil {
+			return err
+		}
+
+		if err := r.AddPromoCode(ctx, code); err != nil {
+			return fmt.Errorf("failed to add promo code in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add promo code. %w", err)
+	}
+
+	return nil
+}
+
+// GetPromoCodes lists codes of the chat to its admins, codes are not meant to
+// be discovered by subscribers.
+func (s *service) GetPromoCodes(ctx context.Context, req model.ChatActor) ([]model.PromoCode, error) {
+	if err := authorize(ctx, s.repo, req.ChatId, req.ActorId, model.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.repo.GetPromoCodes(ctx, req.ChatId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get promo codes in repo. %w", err)
+	}
+
+	return codes, nil
+}
+
+func (s *service) DeletePromoCode(ctx context.Context, req model.DeletePromoCode) error {
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		if err := authorize(ctx, r, req.ChatId, req.ActorId, model.RoleAdmin); err != nil {
+			return err
+		}
+
+		if err := r.DeletePromoCode(ctx, req.ChatId, normalizePromoCode(req.Code)); err != nil {
+			return fmt.Errorf("failed to delete promo code in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete promo code. %w", err)
+	}
+
+	return nil
+}
+
+// GetQuote reads the price without locking anything, the payment itself
+// checks it again.
+func (s *service) GetQuote(ctx context.Context, pay model.Pay) (model.Quote, error) {
+	chat, err := s.repo.GetChat(ctx, pay.ChatId)
+	if err != nil {
+		return model.Quote{}, fmt.Errorf("failed to get chat in repo. %w", err)
+	}
+
+	if chat.Status != model.ChatActive {
+		return model.Quote{}, fmt.Errorf("chat is %s. %w", chat.Status, model.ErrConflict)
+	}
+
+	q, err := s.quote(ctx, s.repo, pay, false)
+	if err != nil {
+		return model.Quote{}, fmt.Errorf("failed to get quote. %w", err)
+	}
+
+	return q, nil
+}
+
+// quote computes the price user pays for chat or one of its plans, applying
+// the promo code if given. With lock the code stays locked until the unit of
+// work r belongs to commits, so its limits hold for concurrent payments.
+func (s *service) quote(ctx context.Context, r repo.Repo, pay model.Pay, lock bool) (model.Quote, error) {
+	var price model.Money
+
+	if pay.PlanId != 0 {
+		plan, err := r.GetPlan(ctx, pay.PlanId, pay.ChatId)
+		if err != nil {
+			return model.Quote{}, fmt.Errorf("failed to get plan in repo. %w", err)
+		}
+
+		price = plan.Price
+	} else {
+		var err error
+
+		price, err = r.GetChatPrice(ctx, pay.ChatId)
+		if err != nil {
+			return model.Quote{}, fmt.Errorf("failed to get chat price in repo. %w", err)
+		}
+	}
+
+	q := model.Quote{
+		PlanId:    pay.PlanId,
+		ListPrice: price,
+		Discount:  model.NewMoney(0, price.Currency),
+		Amount:    price,
+	}
+
+	if pay.PlanId == 0 {
+		locked, ok, err := r.GetLockedPrice(ctx, pay.ChatId, pay.UserId)
+		if err != nil {
+			return model.Quote{}, fmt.Errorf("failed to get locked price in repo. %w", err)
+		}
+
+		if ok && int64(locked) < price.Amount {
+			price = model.NewMoney(int64(locked), price.Currency)
+			q.ListPrice = price
+			q.Amount = price
+			q.Grandfathered = true
+		}
+	}
+
+	code := normalizePromoCode(pay.PromoCode)
+	if code == "" {
+		return q, nil
+	}
+
+	get := r.GetPromoCode
+	if lock {
+		get = r.LockPromoCode
+	}
+
+	promo, err := get(ctx, pay.ChatId, code)
+	if err != nil {
+		return model.Quote{}, fmt.Errorf("failed to get promo code in repo. %w", err)
+	}
+
+	if err := s.checkPromoCode(ctx, r, promo, pay.UserId); err != nil {
+		return model.Quote{}, err
+	}
+
+	switch promo.DiscountType {
+	case model.DiscountPercent:
+		// Rounded to the nearest minor unit, see Money.Percent.
+		q.Discount = price.Percent(int64(promo.DiscountValue))
+	case model.DiscountFixed:
+		q.Discount = model.NewMoney(min(int64(promo.DiscountValue), price.Amount), price.Currency)
+	}
+
+	q.Amount, err = price.Sub(q.Discount)
+	if err != nil {
+		return model.Quote{}, err
+	}
+	q.PromoCode = code
+
+	return q, nil
+}
+
+func (s *service) checkPromoCode(ctx context.Context, r repo.Repo, promo model.PromoCode, user_id int) error {
+	now := time.Now()
+
+	if promo.ValidFrom != nil && now.Before(*promo.ValidFrom) {
+		return fmt.Errorf("promo code is not active yet. %w", model.ErrConflict)
+	}
+
+	if promo.ValidUntil != nil && now.After(*promo.ValidUntil) {
+		return fmt.Errorf("promo code expired. %w", model.ErrConflict)
+	}
+
+	if promo.UsageLimit > 0 && promo.UsedCount >= promo.UsageLimit {
+		return fmt.Errorf("promo code usage limit reached. %w", model.ErrConflict)
+	}
+
+	if promo.PerUserLimit > 0 {
+		used, err := r.CountPromoRedemptions(ctx, promo.ChatId, promo.Code, user_id)
+		if err != nil {
+			return fmt.Errorf("failed to count promo redemptions in repo. %w", err)
+		}
+
+		if used >= promo.PerUserLimit {
+			return fmt.Errorf("promo code per user limit reached. %w", model.ErrConflict)
+		}
+	}
+
+	if promo.FirstPaymentOnly {
+		payments, err := r.CountPayments(ctx, promo.ChatId, user_id)
+		if err != nil {
+			return fmt.Errorf("failed to count payments in repo. %w", err)
+		}
+
+		if payments > 0 {
+			return fmt.Errorf("promo code is valid for the first payment only. %w", model.ErrConflict)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"project/internal/model"
+)
+
+// newPromoService returns service with lifecycleChatId priced at 9.99 RUB.
+func newPromoService(t *testing.T) (*service, *fakeRepo) {
+	t.Helper()
+
+	s, r := newLifecycleService(t)
+
+	chat := r.chats[lifecycleChatId]
+	chat.SetPrice(model.NewMoney(999, "RUB"))
+	r.chats[lifecycleChatId] = chat
+
+	return s, r
+}
+
+func TestGetQuotePromoCode(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+
+	tests := []struct {
+		name     string
+		promo    model.PromoCode
+		uses     int
+		paid     bool
+		discount int64
+		want     error
+	}{
+		{name: "percent rounded up", promo: model.PromoCode{DiscountType: model.DiscountPercent, DiscountValue: 15}, discount: 150},
+		{name: "percent rounded down", promo: model.PromoCode{DiscountType: model.DiscountPercent, DiscountValue: 60}, discount: 599},
+		{name: "percent half rounded up", promo: model.PromoCode{DiscountType: model.DiscountPercent, DiscountValue: 50}, discount: 500},
+		{name: "fixed", promo: model.PromoCode{DiscountType: model.DiscountFixed, DiscountValue: 300}, discount: 300},
+		{name: "fixed above price", promo: model.PromoCode{DiscountType: model.DiscountFixed, DiscountValue: 2000}, discount: 999},
+		{name: "usage limit left", promo: model.PromoCode{DiscountType: model.DiscountFixed, DiscountValue: 100, UsageLimit: 5, UsedCount: 4}, discount: 100},
+		{name: "usage limit reached", promo: model.PromoCode{DiscountType: model.DiscountFixed, DiscountValue: 100, UsageLimit: 5, UsedCount: 5}, want: model.ErrConflict},
+		{name: "per user limit reached", promo: model.PromoCode{DiscountType: model.DiscountFixed, DiscountValue: 100, PerUserLimit: 1}, uses: 1, want: model.ErrConflict},
+		{name: "first payment", promo: model.PromoCode{DiscountType: model.DiscountFixed, DiscountValue: 100, FirstPaymentOnly: true}, discount: 100},
+		{name: "not first payment", promo: model.PromoCode{DiscountType: model.DiscountFixed, DiscountValue: 100, FirstPaymentOnly: true}, paid: true, want: model.ErrConflict},
+		{name: "expired", promo: model.PromoCode{DiscountType: model.DiscountFixed, DiscountValue: 100, ValidUntil: &past}, want: model.ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, r := newPromoService(t)
+
+			tt.promo.ChatId = lifecycleChatId
+			tt.promo.Code = "SALE"
+			r.promoCodes["SALE"] = tt.promo
+			r.promoUses[subscriberId] = tt.uses
+
+			if tt.paid {
+				r.payments[1] = model.Payment{Id: 1, ChatId: lifecycleChatId, UserId: subscriberId}
+			}
+
+			q, err := s.GetQuote(context.Background(), model.Pay{ChatId: lifecycleChatId, UserId: subscriberId, PromoCode: " sale "})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("GetQuote() error = %v, want %v", err, tt.want)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if q.Discount.Amount != tt.discount || q.Amount.Amount != 999-tt.discount || q.PromoCode != "SALE" {
+				t.Fatalf("GetQuote() = %+v, want discount %d", q, tt.discount)
+			}
+
+			if len(r.lockedPromoCodes) != 0 {
+				t.Fatalf("GetQuote() locked promo codes %v", r.lockedPromoCodes)
+			}
+		})
+	}
+}
+
+func TestGetQuoteUnknownPromoCode(t *testing.T) {
+	s, _ := newPromoService(t)
+
+	_, err := s.GetQuote(context.Background(), model.Pay{ChatId: lifecycleChatId, UserId: subscriberId, PromoCode: "nope"})
+	if !errors.Is(err, model.ErrNotFound) {
+		t.Fatalf("GetQuote() error = %v, want %v", err, model.ErrNotFound)
+	}
+}
+
+func TestQuoteLocksPromoCodeForPayment(t *testing.T) {
+	s, r := newPromoService(t)
+
+	r.promoCodes["SALE"] = model.PromoCode{ChatId: lifecycleChatId, Code: "SALE", DiscountType: model.DiscountFixed, DiscountValue: 100}
+
+	if _, err := s.quote(context.Background(), r, model.Pay{ChatId: lifecycleChatId, UserId: subscriberId, PromoCode: "sale"}, true); err != nil {
+		t.Fatalf("quote() error = %v", err)
+	}
+
+	if len(r.lockedPromoCodes) != 1 || r.lockedPromoCodes[0] != "SALE" {
+		t.Fatalf("quote() locked promo codes %v, want [SALE]", r.lockedPromoCodes)
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project/internal/model"
+	"project/internal/repo"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxRatingReasonLength = 500
+
+// ChangeRating sets content rating of chat. It is done by owners and admins
+// and recorded in the rating history.
+func (s *service) ChangeRating(ctx context.Context, req model.ChangeRating) (model.RatingChange, error) {
+	if !slices.Contains(model.ContentRatings, req.Rating) {
+		return model.RatingChange{}, fmt.Errorf("unknown content rating %q. %w", req.Rating, model.ErrInvalidArgument)
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+
+	if utf8.RuneCountInString(req.Reason) > maxRatingReasonLength {
+		return model.RatingChange{}, fmt.Errorf("reason must be at most %d characters. %w", maxRatingReasonLength, model.ErrInvalidArgument)
+	}
+
+	var change model.RatingChange
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		if err := authorize(ctx, r, req.ChatId, req.ActorId, model.RoleAdmin); err != nil {
+			return err
+		}
+
+		chat, err := r.LockChat(ctx, req.ChatId)
+		if err != nil {
+			return fmt.Errorf("failed to lock chat in repo. %w", err)
+		}
+
+		if chat.Status == model.ChatDeleted {
+			return fmt.Errorf("chat is deleted. %w", model.ErrConflict)
+		}
+
+		if chat.ContentRating == req.Rating {
+			return fmt.Errorf("chat is already rated %s. %w", req.Rating, model.ErrConflict)
+		}
+
+		change, err = r.ChangeRating(ctx, model.RatingChange{
+			ChatId:    req.ChatId,
+			OldRating: chat.ContentRating,
+			NewRating: req.Rating,
+			ActorId:   req.ActorId,
+			Reason:    req.Reason,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to change rating in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.RatingChange{}, fmt.Errorf("failed to change rating. %w", err)
+	}
+
+	return change, nil
+}
+
+func (s *service) GetRatingHistory(ctx context.Context, req model.ChatActor) ([]model.RatingChange, error) {
+	if err := authorize(ctx, s.repo, req.ChatId, req.ActorId, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	history, err := s.repo.GetRatingHistory(ctx, req.ChatId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rating history in repo. %w", err)
+	}
+
+	return history, nil
+}
+
+func (s *service) SetAgeVerified(ctx context.Context, req model.SetAgeVerified) error {
+	if req.UserId <= 0 {
+		return fmt.Errorf("user_id is required. %w", model.ErrInvalidArgument)
+	}
+
+	if err := s.repo.SetAgeVerified(ctx, req.UserId, req.Verified); err != nil {
+		return fmt.Errorf("failed to set age verification in repo. %w", err)
+	}
+
+	return nil
+}
+
+// checkAgeGate returns ErrForbidden if chat is age restricted and user has
+// not verified their age.
+func checkAgeGate(ctx context.Context, r repo.Repo, chat model.ChatInfo, user_id int) error {
+	if !slices.Contains(model.AgeRestrictedRatings, chat.ContentRating) {
+		return nil
+	}
+
+	verified, err := r.IsAgeVerified(ctx, user_id)
+	if err != nil {
+		return fmt.Errorf("failed to check age verification in repo. %w", err)
+	}
+
+	if !verified {
+		return fmt.Errorf("chat is rated %s and needs age verification. %w", chat.ContentRating, model.ErrForbidden)
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project/internal/logger"
+	"project/internal/model"
+	"project/internal/repo"
+	"strings"
+)
+
+const maxRefundReasonLength = 500
+
+func (s *service) Refund(ctx context.Context, req model.RefundRequest) (model.Refund, error) {
+	req.Reason = strings.TrimSpace(req.Reason)
+
+	if len(req.Reason) > maxRefundReasonLength {
+		return model.Refund{}, fmt.Errorf("reason must be at most %d characters. %w", maxRefundReasonLength, model.ErrInvalidArgument)
+	}
+
+	if req.Amount < 0 {
+		return model.Refund{}, fmt.Errorf("amount must not be negative. %w", model.ErrInvalidArgument)
+	}
+
+	var (
+		refund  model.Refund
+		payment model.Payment
+		share   float64
+	)
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		if err := authorize(ctx, r, req.ChatId, req.ActorId, model.RoleAdmin); err != nil {
+			return err
+		}
+
+		var err error
+
+		payment, err = r.GetPayment(ctx, req.ChatId, req.PaymentId)
+		if err != nil {
+			return fmt.Errorf("failed to get payment in repo. %w", err)
+		}
+
+		remaining, err := payment.Amount.Sub(payment.Refunded)
+		if err != nil {
+			return err
+		}
+
+		amount := model.NewMoney(req.Amount, remaining.Currency)
+		if req.Amount == 0 {
+			amount = remaining
+		}
+
+		if amount.Amount <= 0 || amount.Amount > remaining.Amount {
+			return fmt.Errorf("refund must be between 1 and %d. %w", remaining.Amount, model.ErrInvalidArgument)
+		}
+
+		refund = model.Refund{
+			PaymentId:     payment.Id,
+			ChatId:        payment.ChatId,
+			UserId:        payment.UserId,
+			Amount:        amount,
+			Reason:        req.Reason,
+			ActorId:       req.ActorId,
+			AccessRevoked: req.RevokeAccess,
+		}
+		share = float64(amount.Amount) / float64(payment.Amount.Amount)
+
+		if payment.TelegramChargeId == "" {
+			refund, err = r.AddRefund(ctx, refund, share)
+			if err != nil {
+				return fmt.Errorf("failed to add refund in repo. %w", err)
+			}
+
+			return nil
+		}
+
+		// Telegram refunds Stars payments only as a whole.
+		if amount != payment.Amount {
+			return fmt.Errorf("telegram stars payment can only be refunded in full. %w", model.ErrInvalidArgument)
+		}
+
+		if s.stars == nil {
+			return fmt.Errorf("telegram is not configured. %w", model.ErrInvalidArgument)
+		}
+
+		refund, err = r.AddPendingRefund(ctx, refund)
+		if err != nil {
+			return fmt.Errorf("failed to add pending refund in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Refund{}, fmt.Errorf("failed to refund payment. %w", err)
+	}
+
+	if refund.Status != model.RefundPending {
+		return refund, nil
+	}
+
+	// Stars are returned after the pending refund is committed, so a
+	// concurrent request cannot refund the payment twice. The refund is
+	// applied only once Telegram accepted it, and settled even if the
+	// client goes away meanwhile.
+	ctx = context.WithoutCancel(ctx)
+
+	if err := s.stars.Refund(ctx, payment); err != nil {
+		if err := s.repo.FailRefund(ctx, refund.Id, err.Error()); err != nil {
+			logger.GetLogger().Err(err).Int64("refund_id", refund.Id).Msg("failed to mark refund failed")
+		}
+
+		return model.Refund{}, fmt.Errorf("failed to refund stars. %w", err)
+	}
+
+	completed, err := s.repo.CompleteRefund(ctx, refund, share)
+	if err != nil {
+		// Telegram has returned the stars, the refund stays pending for
+		// the operator to complete with ResolveRefund.
+		logger.GetLogger().Err(err).Int64("refund_id", refund.Id).Msg("failed to complete refund")
+
+		return model.Refund{}, fmt.Errorf("failed to complete refund in repo. %w", err)
+	}
+
+	return completed, nil
+}
+
+func (s *service) GetRefunds(ctx context.Context, req model.ChatActor) ([]model.Refund, error) {
+	if err := authorize(ctx, s.repo, req.ChatId, req.ActorId, model.RoleViewer); err != nil {
+		return nil, err
+	}
+
+	refunds, err := s.repo.GetRefunds(ctx, req.ChatId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refunds in repo. %w", err)
+	}
+
+	return refunds, nil
+}
+
+// ResolveRefund settles refund left pending when it could not be recorded
+// after the provider answered. The operator checks the provider first.
+func (s *service) ResolveRefund(ctx context.Context, req model.ResolveRefund) (model.Refund, error) {
+	if req.Status != model.RefundCompleted && req.Status != model.RefundFailed {
+		return model.Refund{}, fmt.Errorf("status must be %s or %s. %w", model.RefundCompleted, model.RefundFailed, model.ErrInvalidArgument)
+	}
+
+	refund, err := s.repo.GetRefund(ctx, req.RefundId)
+	if err != nil {
+		return model.Refund{}, fmt.Errorf("failed to get refund in repo. %w", err)
+	}
+
+	if refund.Status != model.RefundPending {
+		return model.Refund{}, fmt.Errorf("refund is %s. %w", refund.Status, model.ErrConflict)
+	}
+
+	if req.Status == model.RefundFailed {
+		if err := s.repo.FailRefund(ctx, refund.Id, req.Error); err != nil {
+			return model.Refund{}, fmt.Errorf("failed to mark refund failed in repo. %w", err)
+		}
+
+		refund.Status = model.RefundFailed
+		refund.Error = req.Error
+
+		return refund, nil
+	}
+
+	payment, err := s.repo.GetPayment(ctx, refund.ChatId, refund.PaymentId)
+	if err != nil {
+		return model.Refund{}, fmt.Errorf("failed to get payment in repo. %w", err)
+	}
+
+	refund, err = s.repo.CompleteRefund(ctx, refund, float64(refund.Amount.Amount)/float64(payment.Amount.Amount))
+	if err != nil {
+		return model.Refund{}, fmt.Errorf("failed to complete refund in repo. %w", err)
+	}
+
+	return refund, nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"project/internal/logger"
+	"project/internal/model"
+	"slices"
+	"time"
+)
+
+const (
+	defaultReminderCheckPeriod = time.Minute
+	remindersBatchSize         = 100
+)
+
+var defaultReminderWindows = []time.Duration{72 * time.Hour, 24 * time.Hour, time.Hour}
+
+// reminderWindows returns windows sorted from the widest one, without
+// duplicates and non positive values.
+func reminderWindows(windows []time.Duration) []time.Duration {
+	res := make([]time.Duration, 0, len(windows))
+
+	for _, w := range windows {
+		if w > 0 && !slices.Contains(res, w) {
+			res = append(res, w)
+		}
+	}
+
+	if len(res) == 0 {
+		res = append(res, defaultReminderWindows...)
+	}
+
+	slices.SortFunc(res, func(a, b time.Duration) int {
+		return cmp.Compare(b, a)
+	})
+
+	return res
+}
+
+// sendReminders notifies subscribers whose access ends within one of the
+// windows. A subscription falls into the narrowest window covering its
+// remaining time, so a late subscriber does not get every reminder at once.
+func (s *service) sendReminders(ctx context.Context) error {
+	now := time.Now()
+
+	for i, window := range s.reminderWindows {
+		var from time.Duration
+
+		if i+1 < len(s.reminderWindows) {
+			from = s.reminderWindows[i+1]
+		}
+
+		reminders, err := s.repo.GetExpiringSubscriptions(ctx, now, from, window, remindersBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to get expiring subscriptions in repo. %w", err)
+		}
+
+		for _, reminder := range reminders {
+			if err := s.sendReminder(ctx, reminder); err != nil {
+				logger.GetLogger().Err(err).Int("chat_id", reminder.ChatId).Int("user_id", reminder.UserId).Msg("failed to send reminder")
+			}
+		}
+	}
+
+	return nil
+}
+
+// sendReminder records reminder before notifying, so concurrent runs do not
+// send it twice. A failed notification is forgotten to be retried on the next
+// run.
+func (s *service) sendReminder(ctx context.Context, reminder model.ExpiryReminder) error {
+	added, err := s.repo.AddReminder(ctx, reminder)
+	if err != nil {
+		return fmt.Errorf("failed to add reminder in repo. %w", err)
+	}
+
+	if !added {
+		return nil
+	}
+
+	if err := s.notifier.NotifyExpiry(ctx, reminder); err != nil {
+		if err := s.repo.DeleteReminder(ctx, reminder); err != nil {
+			logger.GetLogger().Err(err).Int("chat_id", reminder.ChatId).Int("user_id", reminder.UserId).Msg("failed to delete reminder")
+		}
+
+		return fmt.Errorf("failed to notify. %w", err)
+	}
+
+	return nil
+}
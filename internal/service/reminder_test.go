@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"project/internal/model"
+	"project/internal/notifier"
+)
+
+func TestReminderWindows(t *testing.T) {
+	got := reminderWindows([]time.Duration{time.Hour, 0, 72 * time.Hour, time.Hour, -time.Minute})
+	want := []time.Duration{72 * time.Hour, time.Hour}
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("reminderWindows() = %v, want %v", got, want)
+	}
+
+	if got := reminderWindows(nil); !slices.Equal(got, defaultReminderWindows) {
+		t.Fatalf("reminderWindows(nil) = %v, want %v", got, defaultReminderWindows)
+	}
+}
+
+func TestSendReminders(t *testing.T) {
+	ctx := context.Background()
+	expired := time.Now().Add(20 * time.Hour).Truncate(time.Second)
+
+	r := newFakeRepo()
+	r.expiring = []model.ExpiryReminder{
+		{ChatId: 1, UserId: 10, ChatName: "chat", ExpiredDate: expired, Window: 24 * time.Hour},
+		{ChatId: 1, UserId: 11, ChatName: "chat", ExpiredDate: expired, Window: time.Hour},
+	}
+
+	n := &notifier.Fake{}
+	s := &service{repo: r, notifier: n, reminderWindows: reminderWindows(nil)}
+
+	if err := s.sendReminders(ctx); err != nil {
+		t.Fatalf("sendReminders() error = %v", err)
+	}
+
+	if got := n.Reminders(); !slices.Equal(got, r.expiring) {
+		t.Fatalf("reminders = %v, want %v", got, r.expiring)
+	}
+
+	// Recorded reminders are not sent again.
+	if err := s.sendReminders(ctx); err != nil {
+		t.Fatalf("sendReminders() error = %v", err)
+	}
+
+	if got := len(n.Reminders()); got != len(r.expiring) {
+		t.Fatalf("got %d reminders after second run, want %d", got, len(r.expiring))
+	}
+}
+
+func TestSendReminderRetriesFailedNotification(t *testing.T) {
+	ctx := context.Background()
+	reminder := model.ExpiryReminder{ChatId: 1, UserId: 10, ExpiredDate: time.Now().Add(time.Hour), Window: time.Hour}
+
+	r := newFakeRepo()
+	n := &notifier.Fake{Err: errors.New("blocked by user")}
+	s := &service{repo: r, notifier: n}
+
+	if err := s.sendReminder(ctx, reminder); err == nil {
+		t.Fatal("sendReminder() error = nil, want notification error")
+	}
+
+	if r.reminders[reminder] {
+		t.Fatal("failed reminder is still recorded")
+	}
+
+	n.Err = nil
+
+	if err := s.sendReminder(ctx, reminder); err != nil {
+		t.Fatalf("sendReminder() error = %v", err)
+	}
+
+	if got := n.Reminders(); len(got) != 1 || got[0] != reminder {
+		t.Fatalf("reminders = %v, want [%v]", got, reminder)
+	}
+}
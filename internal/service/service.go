@@ -4,52 +4,263 @@ import (
 	"context"
 	"fmt"
 	"project/internal/config"
+	"project/internal/events"
+	"project/internal/logger"
 	"project/internal/model"
+	"project/internal/notifier"
+	"project/internal/payment"
 	"project/internal/repo"
+	"project/internal/telegram"
+	"project/internal/webhook"
+	"project/internal/worker"
+	"time"
 )
 
+const defaultExpiryCheckPeriod = time.Minute
+
 type Service interface {
-	AddNewChat(context.Context, int, int, string, string, int) error
-	GetChatsInfoByOwnerId(context.Context, int) ([]model.ChatInfo, error)
-	DisableChat(context.Context, int) error
-	ChangeDescription(context.Context, int, string) error
-	ChangePrice(context.Context, int, int) error
-	GetAllSlaves(context.Context, int) ([]int, error)
+	AddNewChat(context.Context, model.AddNewChat) error
+	GetChatsInfoByOwnerId(context.Context, model.GetChats) ([]model.ChatInfo, error)
+	DisableChat(context.Context, model.ChatActor) error
+	ChangeChatStatus(context.Context, model.ChangeChatStatus) (model.ChatStatusChange, error)
+	ChangeDescription(context.Context, model.ChangeDescription) error
+	ChangePrice(context.Context, model.ChangePrice) (model.PriceChange, error)
+	GetPriceHistory(context.Context, model.ChatActor) ([]model.PriceChange, error)
+	ChangeTrial(context.Context, model.ChangeTrial) error
+	GetAllSlaves(context.Context, model.ChatActor) ([]int, error)
+
+	// Price, description and subscribers of a chat are available to its
+	// members according to their role.
+	AddChatMember(context.Context, model.AddChatMember) (model.ChatMember, error)
+	GetChatMembers(context.Context, model.ChatActor) ([]model.ChatMember, error)
+	RemoveChatMember(context.Context, model.RemoveChatMember) error
+	InitiateTransfer(context.Context, model.InitiateTransfer) (model.ChatTransfer, error)
+	AcceptTransfer(context.Context, model.ChatActor) (model.ChatTransfer, error)
+	CancelTransfer(context.Context, model.ChatActor) (model.ChatTransfer, error)
+	GetPendingTransfers(context.Context, int) ([]model.ChatTransfer, error)
+
+	GetCatalog(context.Context, model.CatalogRequest) (model.CatalogPage, error)
+	ChangeCatalogInfo(context.Context, model.ChangeCatalogInfo) error
+
+	ChangeRating(context.Context, model.ChangeRating) (model.RatingChange, error)
+	GetRatingHistory(context.Context, model.ChatActor) ([]model.RatingChange, error)
+	// SetAgeVerified records the result of an age check made by the
+	// platform operator.
+	SetAgeVerified(context.Context, model.SetAgeVerified) error
 
 	NewSubscribe(context.Context, int, int) error
 	GetAllSubsciptions(context.Context, int) ([]int, error)
-	Pay(context.Context, int, int) error
+	Pay(context.Context, model.Pay) (model.Payment, error)
 	IsSubscribeExists(context.Context, int, int) (bool, error)
 	IsPaid(context.Context, int, int) (bool, error)
+	GetAccess(context.Context, int, int) (model.Access, error)
+
+	AddWebhook(context.Context, model.AddWebhook) (model.Webhook, error)
+	GetWebhooks(context.Context, model.ChatActor) ([]model.Webhook, error)
+	DeleteWebhook(context.Context, int64, int) error
+	GetOwnerSecret(context.Context, model.OwnerSecret) (string, error)
+	GetWebhookDeliveries(context.Context, int64, int, int) ([]model.WebhookDelivery, error)
+	Redeliver(context.Context, int64, int) error
+
+	// GetSubscriptionEvents returns subscription and payment events after the
+	// given event id, optionally limited to a chat or an owner.
+	GetSubscriptionEvents(context.Context, model.EventCursor, model.EventStream) ([]model.Event, error)
+	GetEventCursor(context.Context) (model.EventCursor, error)
+	// WaitEvents returns channel closed when new events may be available.
+	WaitEvents() <-chan struct{}
+
+	AddPlan(context.Context, model.Plan) (model.Plan, error)
+	GetPlans(context.Context, int) ([]model.Plan, error)
+	UpdatePlan(context.Context, model.Plan) error
+	DeletePlan(context.Context, model.PlanId) error
+
+	AddPromoCode(context.Context, model.PromoCode) error
+	GetPromoCodes(context.Context, model.ChatActor) ([]model.PromoCode, error)
+	DeletePromoCode(context.Context, model.DeletePromoCode) error
+	GetQuote(context.Context, model.Pay) (model.Quote, error)
+
+	// CreateStarsInvoice returns Telegram Stars invoice for access to chat.
+	CreateStarsInvoice(context.Context, model.Pay) (model.Invoice, error)
+	HandlePreCheckout(context.Context, model.PreCheckoutQuery) (model.PreCheckoutResult, error)
+	HandleSuccessfulPayment(context.Context, model.SuccessfulPayment) (model.Payment, error)
+
+	// Refund returns payment or part of it to the user, taking back access it
+	// bought. Telegram Stars payments are refunded through Telegram.
+	Refund(context.Context, model.RefundRequest) (model.Refund, error)
+	GetRefunds(context.Context, model.ChatActor) ([]model.Refund, error)
+	// ResolveRefund completes or fails a refund left pending, for the
+	// platform operator.
+	ResolveRefund(context.Context, model.ResolveRefund) (model.Refund, error)
+
+	GetBalances(context.Context, int) ([]model.Balance, error)
+	GetStatement(context.Context, model.StatementRequest) (model.Statement, error)
+	RequestPayout(context.Context, model.PayoutRequest) (model.Payout, error)
+	GetPayouts(context.Context, int) ([]model.Payout, error)
+	UpdatePayoutStatus(context.Context, model.PayoutStatusUpdate) (model.Payout, error)
+
+	GetAnalytics(context.Context, model.AnalyticsRequest) (model.Analytics, error)
+
+	// ExportSubscribers and ExportPayments call fn for every exported row as
+	// it is read.
+	ExportSubscribers(context.Context, model.ChatActor, func(model.SubscriberExport) error) error
+	ExportPayments(context.Context, model.PaymentsExportRequest, func(model.Payment) error) error
+
+	GetInviteToken(context.Context, int, int) (model.InviteToken, error)
+	RedeemInvite(context.Context, string, int) (model.InviteRedemption, error)
+
+	// HandleJoinRequest approves join request of a user with access to chat
+	// and declines it otherwise.
+	HandleJoinRequest(context.Context, int, int) (bool, error)
+
+	Health(context.Context) model.Health
 
 	Close() error
 }
 
+// NewRequestContext returns context to be used for the calls made while
+// handling one request, so reads observe writes made earlier in it.
+func NewRequestContext(ctx context.Context) context.Context {
+	return repo.WithSession(ctx)
+}
+
 type service struct {
-	repo repo.Repo
+	repo     repo.Repo
+	workers  *worker.Group
+	broker   *events.Broker
+	notifier notifier.Notifier
+	// tg and stars are nil when Telegram is not configured.
+	tg    *telegram.Client
+	stars payment.Provider
+
+	reminderWindows []time.Duration
+	inviteTTL       time.Duration
+	commissionBps   int
+	payoutHold      time.Duration
 }
 
 func NewService(ctx context.Context, cfg config.Config) (Service, error) {
+	var tg *telegram.Client
+
+	if cfg.Telegram.Token != "" {
+		tg = telegram.NewClient(cfg.Telegram)
+	}
+
+	if cfg.Telegram.ManageMembers && tg == nil {
+		return nil, fmt.Errorf("telegram member management requires bot token")
+	}
+
+	if cfg.Payouts.CommissionBasisPoints < 0 || cfg.Payouts.CommissionBasisPoints > maxCommissionBasisPoints {
+		return nil, fmt.Errorf("commission must be between 0 and %d basis points", maxCommissionBasisPoints)
+	}
+
+	n, err := notifier.New(cfg.Reminders, tg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create notifier. %w", err)
+	}
+
 	r, err := repo.NewPgRepo(ctx, cfg.Repo)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new pg repo")
 	}
 
-	return &service{
-		repo: r,
-	}, nil
+	s := &service{
+		repo:     r,
+		workers:  worker.NewGroup(),
+		broker:   events.NewBroker(),
+		notifier: n,
+		tg:       tg,
+
+		reminderWindows: reminderWindows(cfg.Reminders.Windows),
+		inviteTTL:       cfg.Invites.TTL,
+		commissionBps:   cfg.Payouts.CommissionBasisPoints,
+		payoutHold:      cfg.Payouts.HoldPeriod,
+	}
+
+	if tg != nil {
+		s.stars = payment.NewStars(tg)
+	}
+
+	if s.inviteTTL <= 0 {
+		s.inviteTTL = defaultInviteTTL
+	}
+
+	sinks := []events.Sink{s.broker}
+
+	if cfg.Events.LogSink {
+		sinks = append(sinks, events.NewLogSink())
+	}
+
+	for _, url := range cfg.Events.HTTPSinks {
+		sinks = append(sinks, events.NewHTTPSink(url))
+	}
+
+	if cfg.Telegram.ManageMembers {
+		sinks = append(sinks, telegram.NewMemberSink(tg, cfg.Telegram.InviteLinkTTL))
+	}
+
+	s.workers.Add(events.NewDispatcher(r, cfg.Events, sinks...))
+	s.workers.Add(webhook.NewDeliverer(r, cfg.Webhooks))
+
+	expiryPeriod := cfg.Events.ExpiryCheckPeriod
+	if expiryPeriod <= 0 {
+		expiryPeriod = defaultExpiryCheckPeriod
+	}
+
+	s.workers.Add(worker.NewPeriodic("subscription-expiry", expiryPeriod, s.expireSubscriptions))
+
+	reminderPeriod := cfg.Reminders.CheckPeriod
+	if reminderPeriod <= 0 {
+		reminderPeriod = defaultReminderCheckPeriod
+	}
+
+	s.workers.Add(worker.NewPeriodic("expiry-reminders", reminderPeriod, s.sendReminders))
+	s.workers.Add(worker.NewPeriodic("subscriber-counts", subscriberCountPeriod, s.refreshSubscriberCounts))
+
+	// Workers outlive the setup context and are stopped by Close.
+	s.workers.Start(context.WithoutCancel(ctx))
+
+	return s, nil
 }
 
-func (s *service) AddNewChat(ctx context.Context, chat_id int, owner_id int, name string, desciption string, price int) error {
-	if err := s.repo.AddNewChat(ctx, chat_id, owner_id, name, desciption, price); err != nil {
-		return fmt.Errorf("failed to add new chat into repo. %w", err)
+func (s *service) AddNewChat(ctx context.Context, chat model.AddNewChat) error {
+	if err := validateTrialDays(chat.TrialDays); err != nil {
+		return err
+	}
+
+	chat.Currency = model.NormalizeCurrency(chat.Currency)
+	if chat.Currency == "" {
+		chat.Currency = model.DefaultCurrency
+	}
+
+	if err := model.ValidateCurrency(chat.Currency); err != nil {
+		return err
+	}
+
+	if chat.Amount() < 0 {
+		return fmt.Errorf("price must not be negative. %w", model.ErrInvalidArgument)
+	}
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		// A chat given to the bot again starts clean under its new owner.
+		if err := r.ClearDeletedChat(ctx, chat.ChatId); err != nil {
+			return fmt.Errorf("failed to clear deleted chat in repo. %w", err)
+		}
+
+		if err := r.AddNewChat(ctx, chat); err != nil {
+			return fmt.Errorf("failed to add new chat into repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add new chat. %w", err)
 	}
 
 	return nil
 }
 
-func (s *service) GetChatsInfoByOwnerId(ctx context.Context, owner_id int) ([]model.ChatInfo, error) {
-	chats, err := s.repo.GetChatsInfoByOwnerId(ctx, owner_id)
+func (s *service) GetChatsInfoByOwnerId(ctx context.Context, req model.GetChats) ([]model.ChatInfo, error) {
+	chats, err := s.repo.GetChatsInfoByOwnerId(ctx, req.OwnerId, req.IncludeArchived)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chats by owner id in repo. %w", err)
 	}
@@ -57,32 +268,27 @@ func (s *service) GetChatsInfoByOwnerId(ctx context.Context, owner_id int) ([]mo
 	return chats, nil
 }
 
-func (s *service) DisableChat(ctx context.Context, chat_id int) error {
-	if err := s.repo.DisableChat(ctx, chat_id); err != nil {
-		return fmt.Errorf("failed to disable chat in repo. %w", err)
-	}
-
-	return nil
-}
+func (s *service) ChangeDescription(ctx context.Context, change model.ChangeDescription) error {
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		if err := authorize(ctx, r, change.ChatId, change.ActorId, model.RoleAdmin); err != nil {
+			return err
+		}
 
-func (s *service) ChangeDescription(ctx context.Context, chat_id int, description string) error {
-	if err := s.repo.ChangeDescription(ctx, chat_id, description); err != nil {
+		return r.ChangeDescription(ctx, change.ChatId, change.Description)
+	})
+	if err != nil {
 		return fmt.Errorf("failed to change description. %w", err)
 	}
 
 	return nil
 }
 
-func (s *service) ChangePrice(ctx context.Context, chat_id int, price int) error {
-	if err := s.repo.ChangePrice(ctx, chat_id, price); err != nil {
-		return fmt.Errorf("failed to change price. %w", err)
+func (s *service) GetAllSlaves(ctx context.Context, req model.ChatActor) ([]int, error) {
+	if err := authorize(ctx, s.repo, req.ChatId, req.ActorId, model.RoleViewer); err != nil {
+		return nil, err
 	}
 
-	return nil
-}
-
-func (s *service) GetAllSlaves(ctx context.Context, chat_id int) ([]int, error) {
-	slaves, err := s.repo.GetAllSlaves(ctx, chat_id)
+	slaves, err := s.repo.GetAllSlaves(ctx, req.ChatId)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get all slaves. %w", err)
 	}
@@ -91,8 +297,42 @@ func (s *service) GetAllSlaves(ctx context.Context, chat_id int) ([]int, error)
 }
 
 func (s *service) NewSubscribe(ctx context.Context, chat_id int, user_id int) error {
-	if err := s.repo.NewSubscribe(ctx, chat_id, user_id); err != nil {
-		return fmt.Errorf("failed to make new subcribe in repo. %w", err)
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		chat, err := requireActiveChat(ctx, r, chat_id)
+		if err != nil {
+			return err
+		}
+
+		if err := checkAgeGate(ctx, r, chat, user_id); err != nil {
+			return err
+		}
+
+		ok, err := r.IsSubscribeExists(ctx, chat_id, user_id)
+		if err != nil {
+			return fmt.Errorf("failed to check sub exist in repo. %w", err)
+		}
+
+		if ok {
+			return fmt.Errorf("user %d is already subscribed to chat %d. %w", user_id, chat_id, model.ErrConflict)
+		}
+
+		trial, err := s.startTrial(ctx, r, chat_id, user_id)
+		if err != nil {
+			return err
+		}
+
+		if trial {
+			return nil
+		}
+
+		if err := r.NewSubscribe(ctx, chat_id, user_id); err != nil {
+			return fmt.Errorf("failed to make new subcribe in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe. %w", err)
 	}
 
 	return nil
@@ -107,12 +347,82 @@ func (s *service) GetAllSubsciptions(ctx context.Context, user_id int) ([]int, e
 	return subs, nil
 }
 
-func (s *service) Pay(ctx context.Context, chat_id int, user_id int) error {
-	if err := s.repo.Pay(ctx, chat_id, user_id); err != nil {
-		return fmt.Errorf("failed to pay in repo. %w", err)
+func (s *service) Pay(ctx context.Context, pay model.Pay) (model.Payment, error) {
+	token, err := s.newInviteToken(pay.ChatId, pay.UserId)
+	if err != nil {
+		return model.Payment{}, err
 	}
 
-	return nil
+	var payment model.Payment
+
+	err = s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		chat, err := requireActiveChat(ctx, r, pay.ChatId)
+		if err != nil {
+			return err
+		}
+
+		if err := checkAgeGate(ctx, r, chat, pay.UserId); err != nil {
+			return err
+		}
+
+		ok, err := r.IsSubscribeExists(ctx, pay.ChatId, pay.UserId)
+		if err != nil {
+			return fmt.Errorf("failed to check sub exist in repo. %w", err)
+		}
+
+		if !ok {
+			return fmt.Errorf("subscription does not exist. %w", model.ErrNotFound)
+		}
+
+		q, err := s.quote(ctx, r, pay, true)
+		if err != nil {
+			return err
+		}
+
+		payment, err = s.recordPayment(ctx, r, model.Payment{
+			ChatId:    pay.ChatId,
+			UserId:    pay.UserId,
+			PlanId:    q.PlanId,
+			ListPrice: q.ListPrice,
+			Amount:    q.Amount,
+			PromoCode: q.PromoCode,
+		}, token)
+
+		return err
+	})
+	if err != nil {
+		return model.Payment{}, fmt.Errorf("failed to pay. %w", err)
+	}
+
+	return payment, nil
+}
+
+// recordPayment extends the subscription payment was made for, redeems the
+// promo code it used and stores invite token for the paid user.
+func (s *service) recordPayment(ctx context.Context, r repo.Repo, payment model.Payment, token model.InviteToken) (model.Payment, error) {
+	discount, err := payment.ListPrice.Sub(payment.Amount)
+	if err != nil {
+		return model.Payment{}, err
+	}
+
+	payment.CommissionBasisPoints = s.commissionBps
+
+	payment, err = r.Pay(ctx, payment)
+	if err != nil {
+		return model.Payment{}, fmt.Errorf("failed to pay in repo. %w", err)
+	}
+
+	if payment.PromoCode != "" {
+		if err := r.RedeemPromoCode(ctx, payment.ChatId, payment.PromoCode, payment.UserId, payment.Id, discount.Amount); err != nil {
+			return model.Payment{}, fmt.Errorf("failed to redeem promo code in repo. %w", err)
+		}
+	}
+
+	if err := r.AddInviteToken(ctx, token); err != nil {
+		return model.Payment{}, fmt.Errorf("failed to add invite token in repo. %w", err)
+	}
+
+	return payment, nil
 }
 
 func (s *service) IsSubscribeExists(ctx context.Context, chat_id int, users_id int) (bool, error) {
@@ -133,7 +443,67 @@ func (s *service) IsPaid(ctx context.Context, chat_id int, user_id int) (bool, e
 	return ok, nil
 }
 
+// expireSubscriptions deactivates subscriptions past their expired date and
+// emits SubscriptionExpired for each of them.
+func (s *service) expireSubscriptions(ctx context.Context) error {
+	n, err := s.repo.ExpireSubscriptions(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to expire subscriptions in repo. %w", err)
+	}
+
+	if n > 0 {
+		logger.GetLogger().Info().Int("count", n).Msg("expired subscriptions")
+	}
+
+	return nil
+}
+
+func (s *service) Health(ctx context.Context) model.Health {
+	checks := make(map[string]model.HealthCheck)
+
+	checks["postgres"] = healthCheck(s.repo.Ping(ctx))
+	checks["migrations"] = healthCheck(s.repo.CheckMigrations(ctx))
+
+	for name, running := range s.workers.Status() {
+		var err error
+
+		if !running {
+			err = fmt.Errorf("worker is not running")
+		}
+
+		checks["worker:"+name] = healthCheck(err)
+	}
+
+	res := model.Health{
+		Status: model.HealthStatusOk,
+		Checks: checks,
+	}
+
+	for _, c := range checks {
+		if c.Status != model.HealthStatusOk {
+			res.Status = model.HealthStatusFail
+		}
+	}
+
+	return res
+}
+
+func healthCheck(err error) model.HealthCheck {
+	if err != nil {
+		return model.HealthCheck{
+			Status: model.HealthStatusFail,
+			Error:  err.Error(),
+		}
+	}
+
+	return model.HealthCheck{
+		Status: model.HealthStatusOk,
+	}
+}
+
 func (s *service) Close() error {
+	s.workers.Stop()
+
 	if err := s.repo.Close(); err != nil {
 		return fmt.Errorf("failed to close repo. %w", err)
 	}
@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"project/internal/model"
+	"project/internal/repo"
+)
+
+const (
+	invoicePayloadBytes = 16
+
+	// Bot API limits for invoice title and description.
+	maxInvoiceTitle       = 32
+	maxInvoiceDescription = 255
+)
+
+// Reasons shown to the user when checkout is rejected.
+const (
+	checkoutInvoiceNotFound = "Счёт не найден, запросите новый."
+	checkoutAlreadyPaid     = "Счёт уже оплачен."
+	checkoutChatInactive    = "Чат больше не принимает оплату."
+	checkoutPriceChanged    = "Цена изменилась, запросите новый счёт."
+	checkoutAgeRestricted   = "Чат доступен только после подтверждения возраста."
+)
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+
+	return string(r[:n])
+}
+
+func newInvoicePayload() (string, error) {
+	b := make([]byte, invoicePayloadBytes)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate invoice payload. %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func (s *service) CreateStarsInvoice(ctx context.Context, pay model.Pay) (model.Invoice, error) {
+	if s.stars == nil {
+		return model.Invoice{}, fmt.Errorf("telegram is not configured. %w", model.ErrInvalidArgument)
+	}
+
+	payload, err := newInvoicePayload()
+	if err != nil {
+		return model.Invoice{}, err
+	}
+
+	var invoice model.Invoice
+
+	err = s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		chat, err := requireActiveChat(ctx, r, pay.ChatId)
+		if err != nil {
+			return err
+		}
+
+		if err := checkAgeGate(ctx, r, chat, pay.UserId); err != nil {
+			return err
+		}
+
+		ok, err := r.IsSubscribeExists(ctx, pay.ChatId, pay.UserId)
+		if err != nil {
+			return fmt.Errorf("failed to check sub exist in repo. %w", err)
+		}
+
+		if !ok {
+			return fmt.Errorf("subscription does not exist. %w", model.ErrNotFound)
+		}
+
+		q, err := s.quote(ctx, r, pay, true)
+		if err != nil {
+			return err
+		}
+
+		if q.Amount.Currency != model.CurrencyXTR {
+			return fmt.Errorf("chat is priced in %s, not in Telegram Stars. %w", q.Amount.Currency, model.ErrInvalidArgument)
+		}
+
+		if q.Amount.Amount <= 0 {
+			return fmt.Errorf("free access cannot be invoiced. %w", model.ErrInvalidArgument)
+		}
+
+		description := chat.Description
+		if description == "" {
+			description = chat.Name
+		}
+
+		invoice, err = r.AddInvoice(ctx, model.Invoice{
+			ChatId:      pay.ChatId,
+			UserId:      pay.UserId,
+			PlanId:      q.PlanId,
+			PromoCode:   q.PromoCode,
+			Title:       truncate(chat.Name, maxInvoiceTitle),
+			Description: truncate(description, maxInvoiceDescription),
+			Payload:     payload,
+			ListPrice:   q.ListPrice,
+			Amount:      q.Amount,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add invoice in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Invoice{}, fmt.Errorf("failed to create invoice. %w", err)
+	}
+
+	invoice.Link, err = s.stars.CreateInvoice(ctx, invoice)
+	if err != nil {
+		return model.Invoice{}, fmt.Errorf("failed to create invoice link. %w", err)
+	}
+
+	return invoice, nil
+}
+
+// HandlePreCheckout confirms checkout only while the invoice is unpaid, the
+// chat is active and the user would still be charged the invoiced amount.
+func (s *service) HandlePreCheckout(ctx context.Context, query model.PreCheckoutQuery) (model.PreCheckoutResult, error) {
+	if s.stars == nil {
+		return model.PreCheckoutResult{}, fmt.Errorf("telegram is not configured. %w", model.ErrInvalidArgument)
+	}
+
+	var reason string
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		var err error
+
+		reason, err = s.checkPreCheckout(ctx, r, query)
+
+		return err
+	})
+	if err != nil {
+		return model.PreCheckoutResult{}, fmt.Errorf("failed to check checkout. %w", err)
+	}
+
+	if err := s.stars.AnswerPreCheckout(ctx, query.Id, reason); err != nil {
+		return model.PreCheckoutResult{}, fmt.Errorf("failed to answer pre checkout query. %w", err)
+	}
+
+	return model.PreCheckoutResult{
+		Ok:           reason == "",
+		ErrorMessage: reason,
+	}, nil
+}
+
+// checkPreCheckout returns reason to reject checkout, empty if it may proceed.
+func (s *service) checkPreCheckout(ctx context.Context, r repo.Repo, query model.PreCheckoutQuery) (string, error) {
+	invoice, err := r.GetInvoice(ctx, query.InvoicePayload)
+	if errors.Is(err, model.ErrNotFound) {
+		return checkoutInvoiceNotFound, nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get invoice in repo. %w", err)
+	}
+
+	if invoice.Status != model.InvoicePending {
+		return checkoutAlreadyPaid, nil
+	}
+
+	if query.UserId != invoice.UserId {
+		return checkoutInvoiceNotFound, nil
+	}
+
+	chat, err := r.GetChat(ctx, invoice.ChatId)
+	if err != nil {
+		return "", fmt.Errorf("failed to get chat in repo. %w", err)
+	}
+
+	if chat.Status != model.ChatActive {
+		return checkoutChatInactive, nil
+	}
+
+	err = checkAgeGate(ctx, r, chat, invoice.UserId)
+	if errors.Is(err, model.ErrForbidden) {
+		return checkoutAgeRestricted, nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if model.NewMoney(query.TotalAmount, query.Currency) != invoice.Amount {
+		return checkoutPriceChanged, nil
+	}
+
+	q, err := s.quote(ctx, r, model.Pay{
+		ChatId:    invoice.ChatId,
+		UserId:    invoice.UserId,
+		PlanId:    invoice.PlanId,
+		PromoCode: invoice.PromoCode,
+	}, true)
+	if errors.Is(err, model.ErrNotFound) || errors.Is(err, model.ErrConflict) {
+		// Plan was deleted or promo code can no longer be used.
+		return checkoutPriceChanged, nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if q.Amount != invoice.Amount {
+		return checkoutPriceChanged, nil
+	}
+
+	return "", nil
+}
+
+// HandleSuccessfulPayment records payment for the invoice. Telegram has
+// already charged the user, so the invoiced amount is recorded even if the
+// price changed since checkout. Repeated updates return the same payment.
+func (s *service) HandleSuccessfulPayment(ctx context.Context, sp model.SuccessfulPayment) (model.Payment, error) {
+	if sp.TelegramPaymentChargeId == "" {
+		return model.Payment{}, fmt.Errorf("telegram_payment_charge_id is required. %w", model.ErrInvalidArgument)
+	}
+
+	var payment model.Payment
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		invoice, err := r.GetInvoice(ctx, sp.InvoicePayload)
+		if err != nil {
+			return fmt.Errorf("failed to get invoice in repo. %w", err)
+		}
+
+		if invoice.Status == model.InvoicePaid {
+			payment, err = r.GetPayment(ctx, invoice.ChatId, invoice.PaymentId)
+			if err != nil {
+				return fmt.Errorf("failed to get payment in repo. %w", err)
+			}
+
+			if payment.TelegramChargeId != sp.TelegramPaymentChargeId {
+				return fmt.Errorf("invoice is already paid. %w", model.ErrConflict)
+			}
+
+			return nil
+		}
+
+		if sp.UserId != invoice.UserId || model.NewMoney(sp.TotalAmount, sp.Currency) != invoice.Amount {
+			return fmt.Errorf("payment does not match invoice. %w", model.ErrInvalidArgument)
+		}
+
+		ok, err := r.IsSubscribeExists(ctx, invoice.ChatId, invoice.UserId)
+		if err != nil {
+			return fmt.Errorf("failed to check sub exist in repo. %w", err)
+		}
+
+		if !ok {
+			if err := r.NewSubscribe(ctx, invoice.ChatId, invoice.UserId); err != nil {
+				return fmt.Errorf("failed to make new subcribe in repo. %w", err)
+			}
+		}
+
+		token, err := s.newInviteToken(invoice.ChatId, invoice.UserId)
+		if err != nil {
+			return err
+		}
+
+		payment, err = s.recordPayment(ctx, r, model.Payment{
+			ChatId:           invoice.ChatId,
+			UserId:           invoice.UserId,
+			PlanId:           invoice.PlanId,
+			ListPrice:        invoice.ListPrice,
+			Amount:           invoice.Amount,
+			PromoCode:        invoice.PromoCode,
+			TelegramChargeId: sp.TelegramPaymentChargeId,
+		}, token)
+		if err != nil {
+			return err
+		}
+
+		if err := r.MarkInvoicePaid(ctx, invoice.Id, payment.Id); err != nil {
+			return fmt.Errorf("failed to mark invoice paid in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Payment{}, fmt.Errorf("failed to record stars payment. %w", err)
+	}
+
+	return payment, nil
+}
@@ -0,0 +1,421 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"project/internal/config"
+	"project/internal/model"
+	"project/internal/payment"
+	"project/internal/telegram"
+	"project/internal/telegram/telegramtest"
+)
+
+const (
+	starsChatId  = -100
+	starsUserId  = 42
+	starsAdminId = 7
+)
+
+// newStarsService returns service selling access to a chat priced at 250
+// stars to a subscribed user, with Bot API served by the fake server.
+func newStarsService(t *testing.T) (*service, *fakeRepo, *telegramtest.Server) {
+	t.Helper()
+
+	srv := telegramtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	tg := telegram.NewClient(config.Telegram{Token: "token", APIURL: srv.URL})
+
+	r := newFakeRepo()
+	r.chats[starsChatId] = model.ChatInfo{
+		ChatId:        starsChatId,
+		Name:          "Stars chat",
+		Price:         model.NewMoney(250, model.CurrencyXTR),
+		ContentRating: model.RatingGeneral,
+		Status:        model.ChatActive,
+	}
+	r.subscribers[[2]int{starsChatId, starsUserId}] = true
+	r.members[[2]int{starsChatId, starsAdminId}] = model.RoleAdmin
+
+	return &service{repo: r, tg: tg, stars: payment.NewStars(tg)}, r, srv
+}
+
+func createInvoice(t *testing.T, s *service) model.Invoice {
+	t.Helper()
+
+	invoice, err := s.CreateStarsInvoice(context.Background(), model.Pay{ChatId: starsChatId, UserId: starsUserId})
+	if err != nil {
+		t.Fatalf("CreateStarsInvoice() error = %v", err)
+	}
+
+	return invoice
+}
+
+func payInvoice(t *testing.T, s *service, invoice model.Invoice, charge_id string) model.Payment {
+	t.Helper()
+
+	p, err := s.HandleSuccessfulPayment(context.Background(), model.SuccessfulPayment{
+		UserId:                  invoice.UserId,
+		Currency:                invoice.Amount.Currency,
+		TotalAmount:             invoice.Amount.Amount,
+		InvoicePayload:          invoice.Payload,
+		TelegramPaymentChargeId: charge_id,
+	})
+	if err != nil {
+		t.Fatalf("HandleSuccessfulPayment() error = %v", err)
+	}
+
+	return p
+}
+
+func TestCreateStarsInvoice(t *testing.T) {
+	s, r, srv := newStarsService(t)
+
+	invoice := createInvoice(t, s)
+
+	if invoice.Link != "https://t.me/$fakeinvoice1" {
+		t.Fatalf("link = %q", invoice.Link)
+	}
+
+	if invoice.Amount != model.NewMoney(250, model.CurrencyXTR) || invoice.Status != model.InvoicePending {
+		t.Fatalf("invoice = %+v", invoice)
+	}
+
+	if _, ok := r.invoices[invoice.Payload]; !ok {
+		t.Fatal("invoice is not stored")
+	}
+
+	params := srv.CallsTo("createInvoiceLink")[0].Params
+
+	if params["payload"] != invoice.Payload || params["currency"] != model.CurrencyXTR || params["title"] != "Stars chat" {
+		t.Fatalf("createInvoiceLink params = %v", params)
+	}
+}
+
+func TestCreateStarsInvoiceRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(r *fakeRepo)
+		want    error
+	}{
+		{
+			name: "priced in rubles",
+			prepare: func(r *fakeRepo) {
+				chat := r.chats[starsChatId]
+				chat.Price = model.NewMoney(25000, model.CurrencyRUB)
+				r.chats[starsChatId] = chat
+			},
+			want: model.ErrInvalidArgument,
+		},
+		{
+			name: "paused chat",
+			prepare: func(r *fakeRepo) {
+				chat := r.chats[starsChatId]
+				chat.Status = model.ChatPaused
+				r.chats[starsChatId] = chat
+			},
+			want: model.ErrConflict,
+		},
+		{
+			name: "adult chat without age verification",
+			prepare: func(r *fakeRepo) {
+				chat := r.chats[starsChatId]
+				chat.ContentRating = model.RatingAdult
+				r.chats[starsChatId] = chat
+			},
+			want: model.ErrForbidden,
+		},
+		{
+			name: "not subscribed",
+			prepare: func(r *fakeRepo) {
+				delete(r.subscribers, [2]int{starsChatId, starsUserId})
+			},
+			want: model.ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, r, srv := newStarsService(t)
+
+			tt.prepare(r)
+
+			_, err := s.CreateStarsInvoice(context.Background(), model.Pay{ChatId: starsChatId, UserId: starsUserId})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("CreateStarsInvoice() error = %v, want %v", err, tt.want)
+			}
+
+			if calls := srv.Calls(); len(calls) != 0 {
+				t.Fatalf("unexpected Bot API calls %v", calls)
+			}
+		})
+	}
+}
+
+func TestCheckPreCheckout(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(r *fakeRepo, q *model.PreCheckoutQuery)
+		want    string
+	}{
+		{
+			name:    "ok",
+			prepare: func(*fakeRepo, *model.PreCheckoutQuery) {},
+		},
+		{
+			name: "unknown invoice",
+			prepare: func(_ *fakeRepo, q *model.PreCheckoutQuery) {
+				q.InvoicePayload = "unknown"
+			},
+			want: checkoutInvoiceNotFound,
+		},
+		{
+			name: "another user",
+			prepare: func(_ *fakeRepo, q *model.PreCheckoutQuery) {
+				q.UserId = starsUserId + 1
+			},
+			want: checkoutInvoiceNotFound,
+		},
+		{
+			name: "already paid",
+			prepare: func(r *fakeRepo, q *model.PreCheckoutQuery) {
+				invoice := r.invoices[q.InvoicePayload]
+				invoice.Status = model.InvoicePaid
+				r.invoices[q.InvoicePayload] = invoice
+			},
+			want: checkoutAlreadyPaid,
+		},
+		{
+			name: "archived chat",
+			prepare: func(r *fakeRepo, _ *model.PreCheckoutQuery) {
+				chat := r.chats[starsChatId]
+				chat.Status = model.ChatArchived
+				r.chats[starsChatId] = chat
+			},
+			want: checkoutChatInactive,
+		},
+		{
+			name: "age restricted",
+			prepare: func(r *fakeRepo, _ *model.PreCheckoutQuery) {
+				chat := r.chats[starsChatId]
+				chat.ContentRating = model.RatingAdult
+				r.chats[starsChatId] = chat
+			},
+			want: checkoutAgeRestricted,
+		},
+		{
+			name: "price raised after invoice",
+			prepare: func(r *fakeRepo, _ *model.PreCheckoutQuery) {
+				chat := r.chats[starsChatId]
+				chat.Price = model.NewMoney(300, model.CurrencyXTR)
+				r.chats[starsChatId] = chat
+			},
+			want: checkoutPriceChanged,
+		},
+		{
+			name: "amount differs from invoice",
+			prepare: func(_ *fakeRepo, q *model.PreCheckoutQuery) {
+				q.TotalAmount = 1
+			},
+			want: checkoutPriceChanged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, r, srv := newStarsService(t)
+			invoice := createInvoice(t, s)
+
+			q := model.PreCheckoutQuery{
+				Id:             "query",
+				UserId:         starsUserId,
+				Currency:       model.CurrencyXTR,
+				TotalAmount:    invoice.Amount.Amount,
+				InvoicePayload: invoice.Payload,
+			}
+
+			tt.prepare(r, &q)
+
+			res, err := s.HandlePreCheckout(context.Background(), q)
+			if err != nil {
+				t.Fatalf("HandlePreCheckout() error = %v", err)
+			}
+
+			if res.Ok != (tt.want == "") || res.ErrorMessage != tt.want {
+				t.Fatalf("HandlePreCheckout() = %+v, want reason %q", res, tt.want)
+			}
+
+			params := srv.CallsTo("answerPreCheckoutQuery")[0].Params
+
+			if params["pre_checkout_query_id"] != "query" || params["ok"] != res.Ok {
+				t.Fatalf("answerPreCheckoutQuery params = %v", params)
+			}
+		})
+	}
+}
+
+func TestHandleSuccessfulPayment(t *testing.T) {
+	s, r, _ := newStarsService(t)
+	invoice := createInvoice(t, s)
+
+	p := payInvoice(t, s, invoice, "charge")
+
+	if p.Amount != invoice.Amount || p.TelegramChargeId != "charge" {
+		t.Fatalf("payment = %+v", p)
+	}
+
+	if got := r.invoices[invoice.Payload]; got.Status != model.InvoicePaid || got.PaymentId != p.Id {
+		t.Fatalf("invoice = %+v, want paid by %d", got, p.Id)
+	}
+
+	if len(r.tokens) != 1 || r.tokens[0].UserId != starsUserId {
+		t.Fatalf("invite tokens = %+v", r.tokens)
+	}
+
+	// Telegram may repeat the update.
+	if again := payInvoice(t, s, invoice, "charge"); again.Id != p.Id {
+		t.Fatalf("repeated update recorded payment %d, want %d", again.Id, p.Id)
+	}
+
+	if len(r.payments) != 1 {
+		t.Fatalf("got %d payments, want 1", len(r.payments))
+	}
+
+	_, err := s.HandleSuccessfulPayment(context.Background(), model.SuccessfulPayment{
+		UserId:                  starsUserId,
+		Currency:                model.CurrencyXTR,
+		TotalAmount:             invoice.Amount.Amount,
+		InvoicePayload:          invoice.Payload,
+		TelegramPaymentChargeId: "another charge",
+	})
+	if !errors.Is(err, model.ErrConflict) {
+		t.Fatalf("HandleSuccessfulPayment() error = %v, want %v", err, model.ErrConflict)
+	}
+}
+
+func TestHandleSuccessfulPaymentMismatch(t *testing.T) {
+	s, r, _ := newStarsService(t)
+	invoice := createInvoice(t, s)
+
+	_, err := s.HandleSuccessfulPayment(context.Background(), model.SuccessfulPayment{
+		UserId:                  starsUserId,
+		Currency:                model.CurrencyXTR,
+		TotalAmount:             invoice.Amount.Amount - 1,
+		InvoicePayload:          invoice.Payload,
+		TelegramPaymentChargeId: "charge",
+	})
+	if !errors.Is(err, model.ErrInvalidArgument) {
+		t.Fatalf("HandleSuccessfulPayment() error = %v, want %v", err, model.ErrInvalidArgument)
+	}
+
+	if len(r.payments) != 0 {
+		t.Fatalf("got %d payments, want none", len(r.payments))
+	}
+}
+
+func TestRefundStarsPayment(t *testing.T) {
+	s, r, srv := newStarsService(t)
+	p := payInvoice(t, s, createInvoice(t, s), "charge")
+
+	_, err := s.Refund(context.Background(), model.RefundRequest{ChatId: starsChatId, ActorId: starsUserId, PaymentId: p.Id})
+	if !errors.Is(err, model.ErrForbidden) {
+		t.Fatalf("subscriber Refund() error = %v, want %v", err, model.ErrForbidden)
+	}
+
+	_, err = s.Refund(context.Background(), model.RefundRequest{ChatId: starsChatId, ActorId: starsAdminId, PaymentId: p.Id, Amount: 100})
+	if !errors.Is(err, model.ErrInvalidArgument) {
+		t.Fatalf("partial Refund() error = %v, want %v", err, model.ErrInvalidArgument)
+	}
+
+	refund, err := s.Refund(context.Background(), model.RefundRequest{ChatId: starsChatId, ActorId: starsAdminId, PaymentId: p.Id})
+	if err != nil {
+		t.Fatalf("Refund() error = %v", err)
+	}
+
+	if refund.Amount != p.Amount || refund.Status != model.RefundCompleted {
+		t.Fatalf("refund = %+v, want completed for %+v", refund, p.Amount)
+	}
+
+	if got := r.payments[p.Id].Refunded; got != p.Amount {
+		t.Fatalf("refunded = %+v, want %+v", got, p.Amount)
+	}
+
+	calls := srv.CallsTo("refundStarPayment")
+	if len(calls) != 1 {
+		t.Fatalf("got %d refundStarPayment calls, want 1", len(calls))
+	}
+
+	if calls[0].Params["telegram_payment_charge_id"] != "charge" || calls[0].Params["user_id"] != float64(starsUserId) {
+		t.Fatalf("refundStarPayment params = %v", calls[0].Params)
+	}
+
+	if _, err := s.Refund(context.Background(), model.RefundRequest{ChatId: starsChatId, ActorId: starsAdminId, PaymentId: p.Id}); !errors.Is(err, model.ErrInvalidArgument) {
+		t.Fatalf("second Refund() error = %v, want %v", err, model.ErrInvalidArgument)
+	}
+}
+
+func TestRefundStarsPaymentRejectedByTelegram(t *testing.T) {
+	s, r, srv := newStarsService(t)
+	p := payInvoice(t, s, createInvoice(t, s), "charge")
+
+	srv.Fail("refundStarPayment", http.StatusBadRequest, "Bad Request: CHARGE_ALREADY_REFUNDED")
+
+	if _, err := s.Refund(context.Background(), model.RefundRequest{ChatId: starsChatId, ActorId: starsAdminId, PaymentId: p.Id}); err == nil {
+		t.Fatal("Refund() error = nil, want Telegram error")
+	}
+
+	if got := r.payments[p.Id].Refunded.Amount; got != 0 {
+		t.Fatalf("refunded = %d, want 0", got)
+	}
+
+	if len(r.refunds) != 1 || r.refunds[0].Status != model.RefundFailed || r.refunds[0].Error == "" {
+		t.Fatalf("refunds = %+v, want one failed", r.refunds)
+	}
+}
+
+func TestRefundStarsPaymentLeftPending(t *testing.T) {
+	s, r, _ := newStarsService(t)
+	p := payInvoice(t, s, createInvoice(t, s), "charge")
+
+	r.completeRefundErr = errors.New("connection reset")
+
+	ctx := context.Background()
+
+	if _, err := s.Refund(ctx, model.RefundRequest{ChatId: starsChatId, ActorId: starsAdminId, PaymentId: p.Id}); err == nil {
+		t.Fatal("Refund() error = nil, want repo error")
+	}
+
+	if len(r.refunds) != 1 || r.refunds[0].Status != model.RefundPending {
+		t.Fatalf("refunds = %+v, want one pending", r.refunds)
+	}
+
+	if _, err := s.Refund(ctx, model.RefundRequest{ChatId: starsChatId, ActorId: starsAdminId, PaymentId: p.Id}); !errors.Is(err, model.ErrConflict) {
+		t.Fatalf("second Refund() error = %v, want %v", err, model.ErrConflict)
+	}
+
+	id := r.refunds[0].Id
+
+	if _, err := s.ResolveRefund(ctx, model.ResolveRefund{RefundId: id, Status: model.RefundPending}); !errors.Is(err, model.ErrInvalidArgument) {
+		t.Fatalf("ResolveRefund(pending) error = %v, want %v", err, model.ErrInvalidArgument)
+	}
+
+	refund, err := s.ResolveRefund(ctx, model.ResolveRefund{RefundId: id, Status: model.RefundCompleted})
+	if err != nil {
+		t.Fatalf("ResolveRefund() error = %v", err)
+	}
+
+	if refund.Status != model.RefundCompleted {
+		t.Fatalf("refund status = %s, want %s", refund.Status, model.RefundCompleted)
+	}
+
+	if got := r.payments[p.Id].Refunded; got != p.Amount {
+		t.Fatalf("refunded = %+v, want %+v", got, p.Amount)
+	}
+
+	if _, err := s.ResolveRefund(ctx, model.ResolveRefund{RefundId: id, Status: model.RefundFailed}); !errors.Is(err, model.ErrConflict) {
+		t.Fatalf("second ResolveRefund() error = %v, want %v", err, model.ErrConflict)
+	}
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project/internal/model"
+)
+
+func (s *service) HandleJoinRequest(ctx context.Context, chat_id int, user_id int) (bool, error) {
+	if s.tg == nil {
+		return false, fmt.Errorf("telegram is not configured. %w", model.ErrInvalidArgument)
+	}
+
+	access, err := s.repo.GetAccess(ctx, chat_id, user_id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get access in repo. %w", err)
+	}
+
+	if access.Access != model.AccessNone {
+		if err := s.tg.ApproveJoinRequest(ctx, chat_id, user_id); err != nil {
+			return false, fmt.Errorf("failed to approve join request. %w", err)
+		}
+
+		return true, nil
+	}
+
+	if err := s.tg.DeclineJoinRequest(ctx, chat_id, user_id); err != nil {
+		return false, fmt.Errorf("failed to decline join request. %w", err)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"project/internal/model"
+	"project/internal/repo"
+	"time"
+)
+
+const maxTrialDays = 365
+
+func validateTrialDays(trial_days int) error {
+	if trial_days < 0 || trial_days > maxTrialDays {
+		return fmt.Errorf("trial_days must be between 0 and %d. %w", maxTrialDays, model.ErrInvalidArgument)
+	}
+
+	return nil
+}
+
+func (s *service) ChangeTrial(ctx context.Context, req model.ChangeTrial) error {
+	if err := validateTrialDays(req.TrialDays); err != nil {
+		return err
+	}
+
+	err := s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		if err := authorize(ctx, r, req.ChatId, req.ActorId, model.RoleAdmin); err != nil {
+			return err
+		}
+
+		if err := r.ChangeTrial(ctx, req.ChatId, req.TrialDays); err != nil {
+			return fmt.Errorf("failed to change trial in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to change trial. %w", err)
+	}
+
+	return nil
+}
+
+// startTrial subscribes user with trial access if chat offers a trial and
+// the user has not had one in this chat yet. It reports whether the trial
+// was granted.
+func (s *service) startTrial(ctx context.Context, r repo.Repo, chat_id int, user_id int) (bool, error) {
+	trial_days, err := r.GetChatTrialDays(ctx, chat_id)
+	if err != nil {
+		return false, fmt.Errorf("failed to get chat trial in repo. %w", err)
+	}
+
+	if trial_days == 0 {
+		return false, nil
+	}
+
+	started, err := r.StartTrial(ctx, chat_id, user_id)
+	if err != nil {
+		return false, fmt.Errorf("failed to start trial in repo. %w", err)
+	}
+
+	if !started {
+		return false, nil
+	}
+
+	if err := r.NewTrialSubscribe(ctx, chat_id, user_id, time.Now().AddDate(0, 0, trial_days)); err != nil {
+		return false, fmt.Errorf("failed to make trial subscribe in repo. %w", err)
+	}
+
+	return true, nil
+}
+
+func (s *service) GetAccess(ctx context.Context, chat_id int, user_id int) (model.Access, error) {
+	access, err := s.repo.GetAccess(ctx, chat_id, user_id)
+	if err != nil {
+		return model.Access{}, fmt.Errorf("failed to get access in repo. %w", err)
+	}
+
+	return access, nil
+}
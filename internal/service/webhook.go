@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"project/internal/model"
+	"project/internal/repo"
+	"slices"
+)
+
+const (
+	secretBytes            = 32
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 500
+)
+
+func newSecret() (string, error) {
+	b := make([]byte, secretBytes)
+
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate secret. %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func validateWebhook(webhook model.AddWebhook) error {
+	u, err := url.Parse(webhook.Url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("webhook url must be absolute http(s) url. %w", model.ErrInvalidArgument)
+	}
+
+	for _, e := range webhook.Events {
+		if !slices.Contains(model.EventTypes, e) {
+			return fmt.Errorf("unknown event type %q. %w", e, model.ErrInvalidArgument)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) AddWebhook(ctx context.Context, webhook model.AddWebhook) (model.Webhook, error) {
+	if err := validateWebhook(webhook); err != nil {
+		return model.Webhook{}, err
+	}
+
+	if webhook.Events == nil {
+		webhook.Events = make([]string, 0)
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return model.Webhook{}, err
+	}
+
+	var id int64
+
+	err = s.repo.Atomic(ctx, func(ctx context.Context, r repo.Repo) error {
+		if err := authorize(ctx, r, webhook.ChatId, webhook.OwnerId, model.RoleOwner); err != nil {
+			return err
+		}
+
+		// Deliveries are signed with the owner secret, make sure it exists.
+		if _, err := r.SetOwnerSecret(ctx, webhook.OwnerId, secret, false); err != nil {
+			return fmt.Errorf("failed to set owner secret in repo. %w", err)
+		}
+
+		id, err = r.AddWebhook(ctx, webhook)
+		if err != nil {
+			return fmt.Errorf("failed to add webhook in repo. %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to add webhook. %w", err)
+	}
+
+	return model.Webhook{
+		Id:      id,
+		ChatId:  webhook.ChatId,
+		OwnerId: webhook.OwnerId,
+		Url:     webhook.Url,
+		Events:  webhook.Events,
+	}, nil
+}
+
+// GetWebhooks lists chat webhooks. Only the owner sees them, as they reveal
+// where chat events are sent.
+func (s *service) GetWebhooks(ctx context.Context, req model.ChatActor) ([]model.Webhook, error) {
+	if err := authorize(ctx, s.repo, req.ChatId, req.ActorId, model.RoleOwner); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := s.repo.GetWebhooks(ctx, req.ChatId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks in repo. %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (s *service) DeleteWebhook(ctx context.Context, id int64, owner_id int) error {
+	if err := s.repo.DeleteWebhook(ctx, id, owner_id); err != nil {
+		return fmt.Errorf("failed to delete webhook in repo. %w", err)
+	}
+
+	return nil
+}
+
+// GetOwnerSecret returns the secret deliveries of the actor are signed with.
+// The actor must own the given chat.
+func (s *service) GetOwnerSecret(ctx context.Context, req model.OwnerSecret) (string, error) {
+	if err := authorize(ctx, s.repo, req.ChatId, req.ActorId, model.RoleOwner); err != nil {
+		return "", err
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return "", err
+	}
+
+	res, err := s.repo.SetOwnerSecret(ctx, req.ActorId, secret, req.Rotate)
+	if err != nil {
+		return "", fmt.Errorf("failed to set owner secret in repo. %w", err)
+	}
+
+	return res, nil
+}
+
+func (s *service) GetWebhookDeliveries(ctx context.Context, webhook_id int64, owner_id int, limit int) ([]model.WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = defaultDeliveriesLimit
+	}
+
+	limit = min(limit, maxDeliveriesLimit)
+
+	deliveries, err := s.repo.GetWebhookDeliveries(ctx, webhook_id, owner_id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries in repo. %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (s *service) Redeliver(ctx context.Context, delivery_id int64, owner_id int) error {
+	if err := s.repo.Redeliver(ctx, delivery_id, owner_id); err != nil {
+		return fmt.Errorf("failed to redeliver in repo. %w", err)
+	}
+
+	return nil
+}
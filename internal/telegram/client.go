@@ -0,0 +1,218 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"project/internal/config"
+)
+
+const (
+	defaultAPIURL  = "https://api.telegram.org"
+	defaultTimeout = 10 * time.Second
+)
+
+// APIError is an error reported by Bot API itself, as opposed to a failure to
+// reach it.
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram %s failed with %d: %s", e.Method, e.Code, e.Description)
+}
+
+// Permanent reports whether retrying the same call cannot succeed.
+func (e *APIError) Permanent() bool {
+	return e.Code >= 400 && e.Code < 500 && e.Code != http.StatusTooManyRequests
+}
+
+// Client is a minimal Bot API client covering chat member management and
+// Telegram Stars payments.
+type Client struct {
+	url    string
+	client *http.Client
+}
+
+func NewClient(cfg config.Telegram) *Client {
+	apiURL := cfg.APIURL
+	if apiURL == "" {
+		apiURL = defaultAPIURL
+	}
+
+	return &Client{
+		url:    strings.TrimSuffix(apiURL, "/") + "/bot" + cfg.Token + "/",
+		client: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+func (c *Client) call(ctx context.Context, method string, params any, result any) error {
+	b, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal. %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url+method, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("failed to create request. %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s. %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var res struct {
+		Ok          bool            `json:"ok"`
+		ErrorCode   int             `json:"error_code"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("failed to decode %s response. %w", method, err)
+	}
+
+	if !res.Ok {
+		code := res.ErrorCode
+		if code == 0 {
+			code = resp.StatusCode
+		}
+
+		return &APIError{Method: method, Code: code, Description: res.Description}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	if err := json.Unmarshal(res.Result, result); err != nil {
+		return fmt.Errorf("failed to unmarshal %s result. %w", method, err)
+	}
+
+	return nil
+}
+
+func (c *Client) SendMessage(ctx context.Context, chat_id int, text string) error {
+	params := map[string]any{
+		"chat_id": chat_id,
+		"text":    text,
+	}
+
+	return c.call(ctx, "sendMessage", params, nil)
+}
+
+// CreateInviteLink creates link that lets a single user join chat until expire.
+func (c *Client) CreateInviteLink(ctx context.Context, chat_id int, name string, expire time.Time) (string, error) {
+	params := map[string]any{
+		"chat_id":      chat_id,
+		"name":         name,
+		"expire_date":  expire.Unix(),
+		"member_limit": 1,
+	}
+
+	var res struct {
+		InviteLink string `json:"invite_link"`
+	}
+
+	if err := c.call(ctx, "createChatInviteLink", params, &res); err != nil {
+		return "", err
+	}
+
+	return res.InviteLink, nil
+}
+
+func (c *Client) ApproveJoinRequest(ctx context.Context, chat_id int, user_id int) error {
+	params := map[string]any{
+		"chat_id": chat_id,
+		"user_id": user_id,
+	}
+
+	return c.call(ctx, "approveChatJoinRequest", params, nil)
+}
+
+func (c *Client) DeclineJoinRequest(ctx context.Context, chat_id int, user_id int) error {
+	params := map[string]any{
+		"chat_id": chat_id,
+		"user_id": user_id,
+	}
+
+	return c.call(ctx, "declineChatJoinRequest", params, nil)
+}
+
+// BanMember removes user from chat and prevents joining again until unbanned.
+func (c *Client) BanMember(ctx context.Context, chat_id int, user_id int) error {
+	params := map[string]any{
+		"chat_id": chat_id,
+		"user_id": user_id,
+	}
+
+	return c.call(ctx, "banChatMember", params, nil)
+}
+
+// UnbanMember lifts a ban. Users who are not banned are left untouched.
+func (c *Client) UnbanMember(ctx context.Context, chat_id int, user_id int) error {
+	params := map[string]any{
+		"chat_id":        chat_id,
+		"user_id":        user_id,
+		"only_if_banned": true,
+	}
+
+	return c.call(ctx, "unbanChatMember", params, nil)
+}
+
+// CreateInvoiceLink creates link to an invoice for digital goods. For
+// Telegram Stars currency is XTR and amount is in stars.
+func (c *Client) CreateInvoiceLink(ctx context.Context, title string, description string, payload string, currency string, amount int64) (string, error) {
+	params := map[string]any{
+		"title":       title,
+		"description": description,
+		"payload":     payload,
+		"currency":    currency,
+		"prices": []map[string]any{
+			{"label": title, "amount": amount},
+		},
+	}
+
+	var link string
+
+	if err := c.call(ctx, "createInvoiceLink", params, &link); err != nil {
+		return "", err
+	}
+
+	return link, nil
+}
+
+// AnswerPreCheckoutQuery confirms or rejects checkout. errorMessage is shown
+// to the user when checkout is rejected.
+func (c *Client) AnswerPreCheckoutQuery(ctx context.Context, query_id string, ok bool, errorMessage string) error {
+	params := map[string]any{
+		"pre_checkout_query_id": query_id,
+		"ok":                    ok,
+	}
+
+	if !ok {
+		params["error_message"] = errorMessage
+	}
+
+	return c.call(ctx, "answerPreCheckoutQuery", params, nil)
+}
+
+func (c *Client) RefundStarPayment(ctx context.Context, user_id int, charge_id string) error {
+	params := map[string]any{
+		"user_id":                    user_id,
+		"telegram_payment_charge_id": charge_id,
+	}
+
+	return c.call(ctx, "refundStarPayment", params, nil)
+}
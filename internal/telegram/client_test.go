@@ -0,0 +1,102 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"project/internal/config"
+	"project/internal/telegram/telegramtest"
+)
+
+func newTestClient(t *testing.T) (*Client, *telegramtest.Server) {
+	t.Helper()
+
+	srv := telegramtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	return NewClient(config.Telegram{Token: "token", APIURL: srv.URL}), srv
+}
+
+func TestBanMember(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	if err := c.BanMember(context.Background(), -100, 42); err != nil {
+		t.Fatalf("BanMember() error = %v", err)
+	}
+
+	calls := srv.CallsTo("banChatMember")
+	if len(calls) != 1 {
+		t.Fatalf("got %d banChatMember calls, want 1", len(calls))
+	}
+
+	// JSON numbers are decoded as float64.
+	if calls[0].Params["chat_id"] != float64(-100) || calls[0].Params["user_id"] != float64(42) {
+		t.Fatalf("banChatMember params = %v", calls[0].Params)
+	}
+}
+
+func TestCreateInviteLink(t *testing.T) {
+	c, srv := newTestClient(t)
+	expire := time.Now().Add(time.Hour)
+
+	link, err := c.CreateInviteLink(context.Background(), -100, "user 42", expire)
+	if err != nil {
+		t.Fatalf("CreateInviteLink() error = %v", err)
+	}
+
+	if link != "https://t.me/+fake1" {
+		t.Fatalf("link = %q", link)
+	}
+
+	params := srv.CallsTo("createChatInviteLink")[0].Params
+
+	if params["member_limit"] != float64(1) || params["expire_date"] != float64(expire.Unix()) {
+		t.Fatalf("createChatInviteLink params = %v", params)
+	}
+}
+
+func TestCreateInvoiceLink(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	link, err := c.CreateInvoiceLink(context.Background(), "Chat", "Access for 30 days", "payload", "XTR", 250)
+	if err != nil {
+		t.Fatalf("CreateInvoiceLink() error = %v", err)
+	}
+
+	if link != "https://t.me/$fakeinvoice1" {
+		t.Fatalf("link = %q", link)
+	}
+
+	params := srv.CallsTo("createInvoiceLink")[0].Params
+
+	if params["currency"] != "XTR" || params["payload"] != "payload" {
+		t.Fatalf("createInvoiceLink params = %v", params)
+	}
+
+	prices, ok := params["prices"].([]any)
+	if !ok || len(prices) != 1 || prices[0].(map[string]any)["amount"] != float64(250) {
+		t.Fatalf("createInvoiceLink prices = %v", params["prices"])
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	srv.Fail("banChatMember", http.StatusBadRequest, "Bad Request: not enough rights")
+	srv.Fail("createChatInviteLink", http.StatusTooManyRequests, "Too Many Requests")
+
+	var apiErr *APIError
+
+	err := c.BanMember(context.Background(), -100, 42)
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusBadRequest || !apiErr.Permanent() {
+		t.Fatalf("BanMember() error = %v, want permanent APIError", err)
+	}
+
+	_, err = c.CreateInviteLink(context.Background(), -100, "user 42", time.Now())
+	if !errors.As(err, &apiErr) || apiErr.Permanent() {
+		t.Fatalf("CreateInviteLink() error = %v, want temporary APIError", err)
+	}
+}
@@ -0,0 +1,83 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"project/internal/events"
+	"project/internal/logger"
+	"project/internal/model"
+)
+
+const defaultInviteLinkTTL = 24 * time.Hour
+
+type memberSink struct {
+	client        *Client
+	inviteLinkTTL time.Duration
+}
+
+// NewMemberSink returns outbox sink that lets paid users into chats and bans
+// users whose subscription expired or was revoked.
+func NewMemberSink(client *Client, inviteLinkTTL time.Duration) events.Sink {
+	if inviteLinkTTL <= 0 {
+		inviteLinkTTL = defaultInviteLinkTTL
+	}
+
+	return &memberSink{
+		client:        client,
+		inviteLinkTTL: inviteLinkTTL,
+	}
+}
+
+func (s *memberSink) Name() string {
+	return "telegram-members"
+}
+
+// Deliver handles events in order. Errors that Bot API will keep returning,
+// like a bot without admin rights, are logged and skipped so they do not
+// block the outbox, other errors fail the batch to be retried.
+func (s *memberSink) Deliver(ctx context.Context, events []model.Event) error {
+	for _, e := range events {
+		var err error
+
+		switch e.Type {
+		case model.EventPaymentSucceeded, model.EventTrialStarted:
+			err = s.admit(ctx, e.ChatId, e.UserId)
+		case model.EventSubscriptionExpired, model.EventSubscriptionRevoked:
+			err = s.client.BanMember(ctx, e.ChatId, e.UserId)
+		default:
+			continue
+		}
+
+		var apiErr *APIError
+
+		if errors.As(err, &apiErr) && apiErr.Permanent() {
+			logger.GetLogger().Err(err).Int64("event_id", e.Id).Msg("skipping telegram member update")
+
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to handle event %d. %w", e.Id, err)
+		}
+	}
+
+	return nil
+}
+
+// admit lifts a ban left from an expired subscription and sends the user a
+// single use invite link. It is used for both paid and trial access.
+func (s *memberSink) admit(ctx context.Context, chat_id int, user_id int) error {
+	if err := s.client.UnbanMember(ctx, chat_id, user_id); err != nil {
+		return err
+	}
+
+	link, err := s.client.CreateInviteLink(ctx, chat_id, fmt.Sprintf("user %d", user_id), time.Now().Add(s.inviteLinkTTL))
+	if err != nil {
+		return err
+	}
+
+	return s.client.SendMessage(ctx, user_id, "Доступ открыт. Ссылка для входа в чат: "+link)
+}
@@ -0,0 +1,57 @@
+package telegram
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"project/internal/model"
+)
+
+func TestMemberSinkDeliver(t *testing.T) {
+	c, srv := newTestClient(t)
+	s := NewMemberSink(c, time.Hour)
+
+	events := []model.Event{
+		{Id: 1, Type: model.EventPaymentSucceeded, ChatId: -100, UserId: 42},
+		{Id: 2, Type: model.EventSubscriptionCreated, ChatId: -100, UserId: 42},
+		{Id: 3, Type: model.EventSubscriptionExpired, ChatId: -100, UserId: 43},
+	}
+
+	if err := s.Deliver(context.Background(), events); err != nil {
+		t.Fatalf("Deliver() error = %v", err)
+	}
+
+	var methods []string
+
+	for _, c := range srv.Calls() {
+		methods = append(methods, c.Method)
+	}
+
+	want := []string{"unbanChatMember", "createChatInviteLink", "sendMessage", "banChatMember"}
+
+	if !slices.Equal(methods, want) {
+		t.Fatalf("calls = %v, want %v", methods, want)
+	}
+}
+
+func TestMemberSinkSkipsPermanentErrors(t *testing.T) {
+	c, srv := newTestClient(t)
+	s := NewMemberSink(c, time.Hour)
+
+	events := []model.Event{{Id: 1, Type: model.EventSubscriptionRevoked, ChatId: -100, UserId: 42}}
+
+	srv.Fail("banChatMember", http.StatusBadRequest, "Bad Request: not enough rights")
+
+	if err := s.Deliver(context.Background(), events); err != nil {
+		t.Fatalf("Deliver() error = %v, want permanent error skipped", err)
+	}
+
+	srv.Fail("banChatMember", http.StatusTooManyRequests, "Too Many Requests")
+
+	if err := s.Deliver(context.Background(), events); err == nil {
+		t.Fatal("Deliver() error = nil, want temporary error returned")
+	}
+}
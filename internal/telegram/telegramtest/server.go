@@ -0,0 +1,132 @@
+// Package telegramtest provides a fake Bot API server for tests and local runs.
+package telegramtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+// Call is a Bot API request received by the server.
+type Call struct {
+	Method string
+	Params map[string]any
+}
+
+// Server records calls and answers them with canned results. Point
+// config.Telegram.APIURL at URL to use it.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	calls    []Call
+	failures map[string]failure
+	links    int
+	invoices int
+}
+
+type failure struct {
+	code        int
+	description string
+}
+
+func NewServer() *Server {
+	s := &Server{
+		failures: make(map[string]failure),
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+// Fail makes every following call to method return Bot API error code.
+func (s *Server) Fail(method string, code int, description string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[method] = failure{code: code, description: description}
+}
+
+// Calls returns calls received so far.
+func (s *Server) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Call(nil), s.calls...)
+}
+
+// CallsTo returns calls to method received so far.
+func (s *Server) CallsTo(method string) []Call {
+	res := make([]Call, 0)
+
+	for _, c := range s.Calls() {
+		if c.Method == method {
+			res = append(res, c)
+		}
+	}
+
+	return res
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	// Path is /bot<token>/<method>.
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	params := make(map[string]any)
+
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeResponse(w, map[string]any{"ok": false, "error_code": 400, "description": "Bad Request: " + err.Error()})
+
+		return
+	}
+
+	s.mu.Lock()
+	s.calls = append(s.calls, Call{Method: method, Params: params})
+	f, failed := s.failures[method]
+	result := s.result(method, params)
+	s.mu.Unlock()
+
+	if failed {
+		writeResponse(w, map[string]any{"ok": false, "error_code": f.code, "description": f.description})
+
+		return
+	}
+
+	writeResponse(w, map[string]any{"ok": true, "result": result})
+}
+
+// result must be called with mu held.
+func (s *Server) result(method string, params map[string]any) any {
+	switch method {
+	case "createChatInviteLink":
+		s.links++
+
+		return map[string]any{
+			"invite_link":  fmt.Sprintf("https://t.me/+fake%d", s.links),
+			"member_limit": params["member_limit"],
+			"expire_date":  params["expire_date"],
+		}
+	case "createInvoiceLink":
+		s.invoices++
+
+		return fmt.Sprintf("https://t.me/$fakeinvoice%d", s.invoices)
+	case "sendMessage":
+		return map[string]any{
+			"message_id": len(s.calls),
+			"chat":       map[string]any{"id": params["chat_id"]},
+			"text":       params["text"],
+		}
+	default:
+		return true
+	}
+}
+
+func writeResponse(w http.ResponseWriter, body map[string]any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	json.NewEncoder(w).Encode(body)
+}
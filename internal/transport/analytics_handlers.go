@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"project/internal/logger"
+	"project/internal/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const ownersPrefix = "/v1/owners/"
+
+// queryTime parses RFC 3339 time or a date, zero when parameter is missing.
+func queryTime(r *http.Request, name string) (time.Time, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.DateOnly, v); err == nil {
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse %s. %w", name, err)
+	}
+
+	return t, nil
+}
+
+// ownerRoutes serves /v1/owners/{id}/... paths not registered explicitly.
+func (t *transport) ownerRoutes(w http.ResponseWriter, r *http.Request) {
+	id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, ownersPrefix), "/")
+
+	owner_id, err := strconv.Atoi(id)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	switch action {
+	case "analytics":
+		t.getAnalytics(w, r, owner_id)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// getAnalytics takes from, to, granularity and chat_id query parameters.
+func (t *transport) getAnalytics(w http.ResponseWriter, r *http.Request, owner_id int) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req := model.AnalyticsRequest{
+		OwnerId:     owner_id,
+		Granularity: r.URL.Query().Get("granularity"),
+	}
+
+	var err error
+
+	if req.ChatId, err = queryInt(r, "chat_id"); err == nil {
+		if req.From, err = queryTime(r, "from"); err == nil {
+			req.To, err = queryTime(r, "to")
+		}
+	}
+
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to parse analytics query")
+
+		writeError(w, fmt.Errorf("%w. %w", err, model.ErrInvalidArgument), "failed to parse analytics query")
+
+		return
+	}
+
+	analytics, err := t.service.GetAnalytics(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get analytics")
+
+		writeError(w, err, "failed to get analytics")
+
+		return
+	}
+
+	writeJSON(w, analytics, "failed to get analytics")
+}
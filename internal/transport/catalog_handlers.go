@@ -0,0 +1,79 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"project/internal/logger"
+	"project/internal/model"
+)
+
+// getCatalog takes q, category, tag (repeated), currency, sort, cursor, limit
+// and user_id query parameters.
+func (t *transport) getCatalog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	query := r.URL.Query()
+
+	req := model.CatalogRequest{
+		Query:    query.Get("q"),
+		Category: query.Get("category"),
+		Tags:     query["tag"],
+		Currency: query.Get("currency"),
+		Sort:     query.Get("sort"),
+		Cursor:   query.Get("cursor"),
+	}
+
+	var err error
+
+	if req.Limit, err = queryInt(r, "limit"); err == nil {
+		req.UserId, err = queryInt(r, "user_id")
+	}
+
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to parse catalog query")
+
+		writeError(w, fmt.Errorf("%w. %w", err, model.ErrInvalidArgument), "failed to parse catalog query")
+
+		return
+	}
+
+	page, err := t.service.GetCatalog(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get catalog")
+
+		writeError(w, err, "failed to get catalog")
+
+		return
+	}
+
+	writeJSON(w, page, "failed to get catalog")
+}
+
+func (t *transport) changeCatalogInfo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.ChangeCatalogInfo](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	if err := t.service.ChangeCatalogInfo(r.Context(), req); err != nil {
+		logger.GetLogger().Err(err).Msg("failed to change catalog info")
+
+		writeError(w, err, "failed to change catalog info")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
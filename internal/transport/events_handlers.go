@@ -0,0 +1,176 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"project/internal/logger"
+	"project/internal/model"
+	"strconv"
+	"time"
+)
+
+const (
+	streamPollInterval      = time.Second
+	streamHeartbeatInterval = 15 * time.Second
+)
+
+func queryInt(r *http.Request, name string) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return 0, nil
+	}
+
+	res, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s. %w", name, err)
+	}
+
+	return res, nil
+}
+
+// streamEvents pushes subscription and payment events as Server-Sent Events.
+// Clients resume after reconnect with the Last-Event-ID header.
+func (t *transport) streamEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		w.Write([]byte(`{"error": "streaming is not supported"}`))
+
+		return
+	}
+
+	chat_id, err := queryInt(r, "chat_id")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		w.Write([]byte(`{"error": "invalid chat_id"}`))
+
+		return
+	}
+
+	owner_id, err := queryInt(r, "owner_id")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		w.Write([]byte(`{"error": "invalid owner_id"}`))
+
+		return
+	}
+
+	actor_id, err := queryInt(r, "actor_id")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		w.Write([]byte(`{"error": "invalid actor_id"}`))
+
+		return
+	}
+
+	req := model.EventStream{ChatId: chat_id, OwnerId: owner_id, ActorId: actor_id}
+
+	ctx := r.Context()
+
+	var last model.EventCursor
+
+	if id := r.Header.Get("Last-Event-ID"); id != "" {
+		last, err = model.ParseEventCursor(id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			w.Write([]byte(`{"error": "invalid Last-Event-ID"}`))
+
+			return
+		}
+	} else {
+		last, err = t.service.GetEventCursor(ctx)
+		if err != nil {
+			logger.GetLogger().Err(err).Msg("failed to get event cursor")
+
+			w.WriteHeader(http.StatusInternalServerError)
+
+			w.Write([]byte(`{"error": "failed to stream events"}`))
+
+			return
+		}
+	}
+
+	// The first batch is read before the response starts, so access errors
+	// still get their status.
+	wait := t.service.WaitEvents()
+
+	events, err := t.service.GetSubscriptionEvents(ctx, last, req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get subscription events")
+
+		writeError(w, err, "failed to stream events")
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	poll := time.NewTicker(streamPollInterval)
+	defer poll.Stop()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		for _, e := range events {
+			b, err := json.Marshal(e)
+			if err != nil {
+				logger.GetLogger().Err(err).Msg("failed to marshal")
+
+				return
+			}
+
+			if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.Cursor(), e.Type, b); err != nil {
+				return
+			}
+
+			last = e.Cursor()
+		}
+
+		if len(events) > 0 {
+			flusher.Flush()
+		} else {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.done:
+				return
+			case <-wait:
+			case <-poll.C:
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+					return
+				}
+
+				flusher.Flush()
+			}
+		}
+
+		wait = t.service.WaitEvents()
+
+		events, err = t.service.GetSubscriptionEvents(ctx, last, req)
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.GetLogger().Err(err).Msg("failed to get subscription events")
+			}
+
+			return
+		}
+	}
+}
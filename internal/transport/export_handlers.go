@@ -0,0 +1,274 @@
+package transport
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"project/internal/logger"
+	"project/internal/model"
+	"strconv"
+	"time"
+)
+
+const exportFlushRows = 100
+
+// exporter writes rows as CSV or NDJSON, flushing them to the client as it
+// goes. The response starts with the first row, so errors before it are
+// still reported with a status code.
+type exporter struct {
+	w        http.ResponseWriter
+	format   string
+	filename string
+	header   []string
+	csv      *csv.Writer
+	json     *json.Encoder
+	started  bool
+	rows     int
+}
+
+func newExporter(w http.ResponseWriter, format string, filename string, header []string) (*exporter, error) {
+	if format == "" {
+		format = model.ExportCSV
+	}
+
+	if format != model.ExportCSV && format != model.ExportNDJSON {
+		return nil, fmt.Errorf("unknown export format %q. %w", format, model.ErrInvalidArgument)
+	}
+
+	return &exporter{
+		w:        w,
+		format:   format,
+		filename: filename,
+		header:   header,
+	}, nil
+}
+
+func (e *exporter) start() error {
+	if e.started {
+		return nil
+	}
+
+	e.started = true
+
+	contentType := "text/csv; charset=utf-8"
+	if e.format == model.ExportNDJSON {
+		contentType = "application/x-ndjson"
+	}
+
+	e.w.Header().Set("Content-Type", contentType)
+	e.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", e.filename+"."+e.format))
+	e.w.WriteHeader(http.StatusOK)
+
+	if e.format == model.ExportNDJSON {
+		e.json = json.NewEncoder(e.w)
+
+		return nil
+	}
+
+	e.csv = csv.NewWriter(e.w)
+
+	return e.csv.Write(e.header)
+}
+
+// write writes record in CSV or v in NDJSON.
+func (e *exporter) write(record []string, v any) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	var err error
+
+	if e.csv != nil {
+		err = e.csv.Write(record)
+	} else {
+		err = e.json.Encode(v)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write row. %w", err)
+	}
+
+	e.rows++
+
+	if e.rows%exportFlushRows == 0 {
+		return e.flush()
+	}
+
+	return nil
+}
+
+func (e *exporter) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+
+		if err := e.csv.Error(); err != nil {
+			return fmt.Errorf("failed to flush csv. %w", err)
+		}
+	}
+
+	if f, ok := e.w.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	return nil
+}
+
+// finish writes the header of an empty export and flushes what is left.
+func (e *exporter) finish() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+
+	return e.flush()
+}
+
+// fail reports err unless rows were already sent, after which the response
+// can only be cut short.
+func (e *exporter) fail(err error, msg string) {
+	logger.GetLogger().Err(err).Msg(msg)
+
+	if !e.started {
+		writeError(e.w, err, msg)
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+var subscriberHeader = []string{"user_id", "status", "expired_date", "plan_id", "payments", "paid", "currency", "last_paid_at"}
+
+func subscriberRecord(s model.SubscriberExport) []string {
+	return []string{
+		strconv.Itoa(s.UserId),
+		s.Status,
+		formatTime(&s.ExpiredDate),
+		strconv.FormatInt(s.PlanId, 10),
+		strconv.Itoa(s.Payments),
+		strconv.FormatInt(s.Paid.Amount, 10),
+		s.Paid.Currency,
+		formatTime(s.LastPaidAt),
+	}
+}
+
+var paymentHeader = []string{
+	"id", "chat_id", "user_id", "plan_id", "list_price", "amount", "refunded", "currency", "commission_basis_points",
+	"promo_code", "telegram_payment_charge_id", "refunded_at", "created_at",
+}
+
+func paymentRecord(p model.Payment) []string {
+	return []string{
+		strconv.FormatInt(p.Id, 10),
+		strconv.Itoa(p.ChatId),
+		strconv.Itoa(p.UserId),
+		strconv.FormatInt(p.PlanId, 10),
+		strconv.FormatInt(p.ListPrice.Amount, 10),
+		strconv.FormatInt(p.Amount.Amount, 10),
+		strconv.FormatInt(p.Refunded.Amount, 10),
+		p.Amount.Currency,
+		strconv.Itoa(p.CommissionBasisPoints),
+		p.PromoCode,
+		p.TelegramChargeId,
+		formatTime(p.RefundedAt),
+		formatTime(&p.CreatedAt),
+	}
+}
+
+// exportSubscribers streams subscribers of chat_id to its member actor_id in
+// format csv or ndjson.
+func (t *transport) exportSubscribers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	var (
+		req model.ChatActor
+		err error
+	)
+
+	if req.ChatId, err = queryInt(r, "chat_id"); err == nil {
+		req.ActorId, err = queryInt(r, "actor_id")
+	}
+
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to parse export query")
+
+		writeError(w, fmt.Errorf("%w. %w", err, model.ErrInvalidArgument), "failed to parse export query")
+
+		return
+	}
+
+	e, err := newExporter(w, r.URL.Query().Get("format"), fmt.Sprintf("subscribers-%d", req.ChatId), subscriberHeader)
+	if err != nil {
+		writeError(w, err, "failed to export subscribers")
+
+		return
+	}
+
+	err = t.service.ExportSubscribers(r.Context(), req, func(s model.SubscriberExport) error {
+		return e.write(subscriberRecord(s), s)
+	})
+	if err == nil {
+		err = e.finish()
+	}
+
+	if err != nil {
+		e.fail(err, "failed to export subscribers")
+	}
+}
+
+// exportPayments streams payments of owner_id made between from and to to the
+// owner, who is actor_id, in format csv or ndjson.
+func (t *transport) exportPayments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	var (
+		req model.PaymentsExportRequest
+		err error
+	)
+
+	if req.OwnerId, err = queryInt(r, "owner_id"); err == nil {
+		if req.ActorId, err = queryInt(r, "actor_id"); err == nil {
+			if req.From, err = queryTime(r, "from"); err == nil {
+				req.To, err = queryTime(r, "to")
+			}
+		}
+	}
+
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to parse export query")
+
+		writeError(w, fmt.Errorf("%w. %w", err, model.ErrInvalidArgument), "failed to parse export query")
+
+		return
+	}
+
+	e, err := newExporter(w, r.URL.Query().Get("format"), fmt.Sprintf("payments-%d", req.OwnerId), paymentHeader)
+	if err != nil {
+		writeError(w, err, "failed to export payments")
+
+		return
+	}
+
+	err = t.service.ExportPayments(r.Context(), req, func(p model.Payment) error {
+		return e.write(paymentRecord(p), p)
+	})
+	if err == nil {
+		err = e.finish()
+	}
+
+	if err != nil {
+		e.fail(err, "failed to export payments")
+	}
+}
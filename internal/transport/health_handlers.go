@@ -0,0 +1,57 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"project/internal/logger"
+	"project/internal/model"
+)
+
+func (t *transport) healthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	w.Write([]byte(`{"status": "ok"}`))
+}
+
+func (t *transport) readyz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	if t.shuttingDown.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+
+		w.Write([]byte(`{"status": "shutting_down"}`))
+
+		return
+	}
+
+	health := t.service.Health(r.Context())
+
+	b, err := json.Marshal(health)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to marshal")
+
+		w.WriteHeader(http.StatusInternalServerError)
+
+		w.Write([]byte(`{"error": "failed to check readiness"}`))
+
+		return
+	}
+
+	if health.Status != model.HealthStatusOk {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	w.Write(b)
+}
@@ -0,0 +1,59 @@
+package transport
+
+import (
+	"net/http"
+	"project/internal/logger"
+	"project/internal/model"
+)
+
+func (t *transport) getInviteToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.NewSubscribe](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	token, err := t.service.GetInviteToken(r.Context(), req.ChatId, req.UserId)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get invite token")
+
+		writeError(w, err, "failed to get invite token")
+
+		return
+	}
+
+	writeJSON(w, token, "failed to get invite token")
+}
+
+func (t *transport) redeemInvite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.RedeemInvite](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	res, err := t.service.RedeemInvite(r.Context(), req.Token, req.UserId)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to redeem invite")
+
+		writeError(w, err, "failed to redeem invite")
+
+		return
+	}
+
+	writeJSON(w, res, "failed to redeem invite")
+}
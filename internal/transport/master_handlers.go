@@ -48,12 +48,10 @@ func (t *transport) addNewChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := t.service.AddNewChat(r.Context(), req.ChatId, req.OwnerId, req.Name, req.Description, req.Price); err != nil {
+	if err := t.service.AddNewChat(r.Context(), req); err != nil {
 		logger.GetLogger().Err(err).Msg("failed to add new chat")
 
-		w.WriteHeader(http.StatusInternalServerError)
-
-		w.Write([]byte(`{"error": "failed to add new chat"}`))
+		writeError(w, err, "failed to add new chat")
 
 		return
 	}
@@ -68,14 +66,14 @@ func (t *transport) getChatsInfoByOwnerId(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	req, err := unmarshalData[model.Owner](w, r)
+	req, err := unmarshalData[model.GetChats](w, r)
 	if err != nil {
 		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
 
 		return
 	}
 
-	data, err := t.service.GetChatsInfoByOwnerId(r.Context(), req.OwnerId)
+	data, err := t.service.GetChatsInfoByOwnerId(r.Context(), req)
 	if err != nil {
 		logger.GetLogger().Err(err).Msg("failed to get chats by chat id")
 
@@ -109,19 +107,17 @@ func (t *transport) disableChat(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	req, err := unmarshalData[model.Chat](w, r)
+	req, err := unmarshalData[model.ChatActor](w, r)
 	if err != nil {
 		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
 
 		return
 	}
 
-	if err := t.service.DisableChat(r.Context(), req.ChatId); err != nil {
+	if err := t.service.DisableChat(r.Context(), req); err != nil {
 		logger.GetLogger().Err(err).Msg("failed to disable chat")
 
-		w.WriteHeader(http.StatusInternalServerError)
-
-		w.Write([]byte(`{"error": "failed to disable chat"}`))
+		writeError(w, err, "failed to disable chat")
 
 		return
 	}
@@ -143,12 +139,10 @@ func (t *transport) changeDescription(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := t.service.ChangeDescription(r.Context(), req.ChatId, req.Description); err != nil {
+	if err := t.service.ChangeDescription(r.Context(), req); err != nil {
 		logger.GetLogger().Err(err).Msg("failed to change description")
 
-		w.WriteHeader(http.StatusInternalServerError)
-
-		w.Write([]byte(`{"error": "failed to change description"}`))
+		writeError(w, err, "failed to change description")
 
 		return
 	}
@@ -170,12 +164,36 @@ func (t *transport) changePrice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := t.service.ChangePrice(r.Context(), req.ChatId, req.Price); err != nil {
+	change, err := t.service.ChangePrice(r.Context(), req)
+	if err != nil {
 		logger.GetLogger().Err(err).Msg("failed to change price")
 
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, err, "failed to change price")
+
+		return
+	}
+
+	writeJSON(w, change, "failed to change price")
+}
+
+func (t *transport) changeTrial(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.ChangeTrial](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
 
-		w.Write([]byte(`{"error": "failed to change price"}`))
+	if err := t.service.ChangeTrial(r.Context(), req); err != nil {
+		logger.GetLogger().Err(err).Msg("failed to change trial")
+
+		writeError(w, err, "failed to change trial")
 
 		return
 	}
@@ -190,20 +208,18 @@ func (t *transport) getAllSlaves(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	req, err := unmarshalData[model.Chat](w, r)
+	req, err := unmarshalData[model.ChatActor](w, r)
 	if err != nil {
 		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
 
 		return
 	}
 
-	slaves, err := t.service.GetAllSlaves(r.Context(), req.ChatId)
+	slaves, err := t.service.GetAllSlaves(r.Context(), req)
 	if err != nil {
 		logger.GetLogger().Err(err).Msg("failed to get all slaves")
 
-		w.WriteHeader(http.StatusInternalServerError)
-
-		w.Write([]byte(`{"error": "failed to get all slaves"}`))
+		writeError(w, err, "failed to get all slaves")
 
 		return
 	}
@@ -223,3 +239,107 @@ func (t *transport) getAllSlaves(w http.ResponseWriter, r *http.Request) {
 
 	w.Write(b)
 }
+
+func (t *transport) getPriceHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.ChatActor](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	history, err := t.service.GetPriceHistory(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get price history")
+
+		writeError(w, err, "failed to get price history")
+
+		return
+	}
+
+	writeJSON(w, history, "failed to get price history")
+}
+
+func (t *transport) changeChatStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.ChangeChatStatus](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	change, err := t.service.ChangeChatStatus(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to change chat status")
+
+		writeError(w, err, "failed to change chat status")
+
+		return
+	}
+
+	writeJSON(w, change, "failed to change chat status")
+}
+
+func (t *transport) changeRating(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.ChangeRating](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	change, err := t.service.ChangeRating(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to change rating")
+
+		writeError(w, err, "failed to change rating")
+
+		return
+	}
+
+	writeJSON(w, change, "failed to change rating")
+}
+
+func (t *transport) getRatingHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.ChatActor](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	history, err := t.service.GetRatingHistory(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get rating history")
+
+		writeError(w, err, "failed to get rating history")
+
+		return
+	}
+
+	writeJSON(w, history, "failed to get rating history")
+}
@@ -0,0 +1,188 @@
+package transport
+
+import (
+	"net/http"
+	"project/internal/logger"
+	"project/internal/model"
+)
+
+func (t *transport) addChatMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.AddChatMember](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	member, err := t.service.AddChatMember(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to add chat member")
+
+		writeError(w, err, "failed to add chat member")
+
+		return
+	}
+
+	writeJSON(w, member, "failed to add chat member")
+}
+
+func (t *transport) getChatMembers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.ChatActor](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	members, err := t.service.GetChatMembers(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get chat members")
+
+		writeError(w, err, "failed to get chat members")
+
+		return
+	}
+
+	writeJSON(w, members, "failed to get chat members")
+}
+
+func (t *transport) removeChatMember(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.RemoveChatMember](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	if err := t.service.RemoveChatMember(r.Context(), req); err != nil {
+		logger.GetLogger().Err(err).Msg("failed to remove chat member")
+
+		writeError(w, err, "failed to remove chat member")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (t *transport) initiateTransfer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.InitiateTransfer](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	transfer, err := t.service.InitiateTransfer(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to initiate transfer")
+
+		writeError(w, err, "failed to initiate transfer")
+
+		return
+	}
+
+	writeJSON(w, transfer, "failed to initiate transfer")
+}
+
+func (t *transport) acceptTransfer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.ChatActor](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	transfer, err := t.service.AcceptTransfer(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to accept transfer")
+
+		writeError(w, err, "failed to accept transfer")
+
+		return
+	}
+
+	writeJSON(w, transfer, "failed to accept transfer")
+}
+
+func (t *transport) cancelTransfer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.ChatActor](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	transfer, err := t.service.CancelTransfer(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to cancel transfer")
+
+		writeError(w, err, "failed to cancel transfer")
+
+		return
+	}
+
+	writeJSON(w, transfer, "failed to cancel transfer")
+}
+
+func (t *transport) getPendingTransfers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.GetAllSubs](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	transfers, err := t.service.GetPendingTransfers(r.Context(), req.UserId)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get pending transfers")
+
+		writeError(w, err, "failed to get pending transfers")
+
+		return
+	}
+
+	writeJSON(w, transfers, "failed to get pending transfers")
+}
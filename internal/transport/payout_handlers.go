@@ -0,0 +1,137 @@
+package transport
+
+import (
+	"net/http"
+	"project/internal/logger"
+	"project/internal/model"
+)
+
+func (t *transport) getBalances(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.Owner](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	balances, err := t.service.GetBalances(r.Context(), req.OwnerId)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get balance")
+
+		writeError(w, err, "failed to get balance")
+
+		return
+	}
+
+	writeJSON(w, balances, "failed to get balance")
+}
+
+func (t *transport) getStatement(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.StatementRequest](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	statement, err := t.service.GetStatement(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get statement")
+
+		writeError(w, err, "failed to get statement")
+
+		return
+	}
+
+	writeJSON(w, statement, "failed to get statement")
+}
+
+func (t *transport) requestPayout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.PayoutRequest](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	payout, err := t.service.RequestPayout(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to request payout")
+
+		writeError(w, err, "failed to request payout")
+
+		return
+	}
+
+	writeJSON(w, payout, "failed to request payout")
+}
+
+func (t *transport) getPayouts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.Owner](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	payouts, err := t.service.GetPayouts(r.Context(), req.OwnerId)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get payouts")
+
+		writeError(w, err, "failed to get payouts")
+
+		return
+	}
+
+	writeJSON(w, payouts, "failed to get payouts")
+}
+
+func (t *transport) updatePayoutStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.PayoutStatusUpdate](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	payout, err := t.service.UpdatePayoutStatus(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to update payout status")
+
+		writeError(w, err, "failed to update payout status")
+
+		return
+	}
+
+	writeJSON(w, payout, "failed to update payout status")
+}
@@ -0,0 +1,109 @@
+package transport
+
+import (
+	"net/http"
+	"project/internal/logger"
+	"project/internal/model"
+)
+
+func (t *transport) addPlan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.Plan](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	plan, err := t.service.AddPlan(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to add plan")
+
+		writeError(w, err, "failed to add plan")
+
+		return
+	}
+
+	writeJSON(w, plan, "failed to add plan")
+}
+
+func (t *transport) getPlans(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.Chat](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	plans, err := t.service.GetPlans(r.Context(), req.ChatId)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get plans")
+
+		writeError(w, err, "failed to get plans")
+
+		return
+	}
+
+	writeJSON(w, plans, "failed to get plans")
+}
+
+func (t *transport) updatePlan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.Plan](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	if err := t.service.UpdatePlan(r.Context(), req); err != nil {
+		logger.GetLogger().Err(err).Msg("failed to update plan")
+
+		writeError(w, err, "failed to update plan")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (t *transport) deletePlan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.PlanId](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	if err := t.service.DeletePlan(r.Context(), req); err != nil {
+		logger.GetLogger().Err(err).Msg("failed to delete plan")
+
+		writeError(w, err, "failed to delete plan")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
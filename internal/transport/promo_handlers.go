@@ -0,0 +1,109 @@
+package transport
+
+import (
+	"net/http"
+	"project/internal/logger"
+	"project/internal/model"
+)
+
+func (t *transport) addPromoCode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.PromoCode](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	if err := t.service.AddPromoCode(r.Context(), req); err != nil {
+		logger.GetLogger().Err(err).Msg("failed to add promo code")
+
+		writeError(w, err, "failed to add promo code")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (t *transport) getPromoCodes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.ChatActor](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	codes, err := t.service.GetPromoCodes(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get promo codes")
+
+		writeError(w, err, "failed to get promo codes")
+
+		return
+	}
+
+	writeJSON(w, codes, "failed to get promo codes")
+}
+
+func (t *transport) deletePromoCode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.DeletePromoCode](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	if err := t.service.DeletePromoCode(r.Context(), req); err != nil {
+		logger.GetLogger().Err(err).Msg("failed to delete promo code")
+
+		writeError(w, err, "failed to delete promo code")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (t *transport) getQuote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.Pay](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	quote, err := t.service.GetQuote(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get quote")
+
+		writeError(w, err, "failed to get quote")
+
+		return
+	}
+
+	writeJSON(w, quote, "failed to get quote")
+}
@@ -0,0 +1,85 @@
+package transport
+
+import (
+	"net/http"
+	"project/internal/logger"
+	"project/internal/model"
+)
+
+func (t *transport) refund(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.RefundRequest](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	refund, err := t.service.Refund(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to refund payment")
+
+		writeError(w, err, "failed to refund payment")
+
+		return
+	}
+
+	writeJSON(w, refund, "failed to refund payment")
+}
+
+func (t *transport) getRefunds(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.ChatActor](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	refunds, err := t.service.GetRefunds(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get refunds")
+
+		writeError(w, err, "failed to get refunds")
+
+		return
+	}
+
+	writeJSON(w, refunds, "failed to get refunds")
+}
+
+func (t *transport) resolveRefund(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.ResolveRefund](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	refund, err := t.service.ResolveRefund(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to resolve refund")
+
+		writeError(w, err, "failed to resolve refund")
+
+		return
+	}
+
+	writeJSON(w, refund, "failed to resolve refund")
+}
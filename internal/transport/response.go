@@ -0,0 +1,47 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"project/internal/logger"
+	"project/internal/model"
+)
+
+// writeError responds with status matching err and msg as error text.
+func writeError(w http.ResponseWriter, err error, msg string) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, model.ErrInvalidArgument):
+		status = http.StatusBadRequest
+	case errors.Is(err, model.ErrConflict):
+		status = http.StatusConflict
+	case errors.Is(err, model.ErrForbidden):
+		status = http.StatusForbidden
+	}
+
+	b, _ := json.Marshal(map[string]string{"error": msg})
+
+	w.WriteHeader(status)
+
+	w.Write(b)
+}
+
+// writeJSON responds with data marshaled to JSON.
+func writeJSON(w http.ResponseWriter, data any, msg string) {
+	b, err := json.Marshal(data)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to marshal")
+
+		writeError(w, err, msg)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	w.Write(b)
+}
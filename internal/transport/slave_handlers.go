@@ -24,9 +24,7 @@ func (t *transport) newSubscribe(w http.ResponseWriter, r *http.Request) {
 	if err := t.service.NewSubscribe(r.Context(), req.ChatId, req.UserId); err != nil {
 		logger.GetLogger().Err(err).Msg("failed to add new subscribe")
 
-		w.WriteHeader(http.StatusInternalServerError)
-
-		w.Write([]byte(`{"error": "failed to add new subscribe"}`))
+		writeError(w, err, "failed to add new subscribe")
 
 		return
 	}
@@ -82,24 +80,23 @@ func (t *transport) pay(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	req, err := unmarshalData[model.NewSubscribe](w, r)
+	req, err := unmarshalData[model.Pay](w, r)
 	if err != nil {
 		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
 
 		return
 	}
 
-	if err := t.service.Pay(r.Context(), req.ChatId, req.UserId); err != nil {
+	payment, err := t.service.Pay(r.Context(), req)
+	if err != nil {
 		logger.GetLogger().Err(err).Msg("failed to pay")
 
-		w.WriteHeader(http.StatusInternalServerError)
-
-		w.Write([]byte(`{"error": "failed to pay"}`))
+		writeError(w, err, "failed to pay")
 
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	writeJSON(w, payment, "failed to pay")
 }
 
 func (t *transport) isSubscribeExists(w http.ResponseWriter, r *http.Request) {
@@ -183,3 +180,54 @@ func (t *transport) isPaid(w http.ResponseWriter, r *http.Request) {
 
 	w.Write(b)
 }
+
+func (t *transport) getAccess(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.NewSubscribe](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	access, err := t.service.GetAccess(r.Context(), req.ChatId, req.UserId)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get access")
+
+		writeError(w, err, "failed to get access")
+
+		return
+	}
+
+	writeJSON(w, access, "failed to get access")
+}
+
+func (t *transport) setAgeVerified(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.SetAgeVerified](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	if err := t.service.SetAgeVerified(r.Context(), req); err != nil {
+		logger.GetLogger().Err(err).Msg("failed to set age verification")
+
+		writeError(w, err, "failed to set age verification")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,87 @@
+package transport
+
+import (
+	"net/http"
+	"project/internal/logger"
+	"project/internal/model"
+)
+
+func (t *transport) createStarsInvoice(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.Pay](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	invoice, err := t.service.CreateStarsInvoice(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to create stars invoice")
+
+		writeError(w, err, "failed to create stars invoice")
+
+		return
+	}
+
+	writeJSON(w, invoice, "failed to create stars invoice")
+}
+
+// preCheckout handles pre_checkout_query updates forwarded by the bot.
+func (t *transport) preCheckout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.PreCheckoutQuery](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	res, err := t.service.HandlePreCheckout(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to handle pre checkout query")
+
+		writeError(w, err, "failed to handle pre checkout query")
+
+		return
+	}
+
+	writeJSON(w, res, "failed to handle pre checkout query")
+}
+
+// successfulPayment handles successful_payment messages forwarded by the bot.
+func (t *transport) successfulPayment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.SuccessfulPayment](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	payment, err := t.service.HandleSuccessfulPayment(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to handle successful payment")
+
+		writeError(w, err, "failed to handle successful payment")
+
+		return
+	}
+
+	writeJSON(w, payment, "failed to handle successful payment")
+}
@@ -0,0 +1,34 @@
+package transport
+
+import (
+	"net/http"
+	"project/internal/logger"
+	"project/internal/model"
+)
+
+// joinRequest handles chat_join_request updates forwarded by the bot.
+func (t *transport) joinRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.JoinRequest](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	approved, err := t.service.HandleJoinRequest(r.Context(), req.ChatId, req.UserId)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to handle join request")
+
+		writeError(w, err, "failed to handle join request")
+
+		return
+	}
+
+	writeJSON(w, model.JoinRequestResult{Approved: approved}, "failed to handle join request")
+}
@@ -2,13 +2,21 @@ package transport
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"project/internal/config"
+	"project/internal/logger"
 	"project/internal/service"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
+const defaultShutdownTimeout = 10 * time.Second
+
 type Transport interface {
 	Run() error
 	Close() error
@@ -17,6 +25,11 @@ type Transport interface {
 type transport struct {
 	router  *http.Server
 	service service.Service
+	cfg     config.Transport
+
+	shuttingDown atomic.Bool
+	// done is closed when shutdown starts to end long-lived streams.
+	done chan struct{}
 }
 
 func NewTransport(ctx context.Context, cfg config.Config) (Transport, error) {
@@ -32,6 +45,8 @@ func NewTransport(ctx context.Context, cfg config.Config) (Transport, error) {
 	tr := transport{
 		router:  router,
 		service: s,
+		cfg:     cfg.Transport,
+		done:    make(chan struct{}),
 	}
 
 	tr.setupRoutes()
@@ -42,6 +57,9 @@ func NewTransport(ctx context.Context, cfg config.Config) (Transport, error) {
 func (t *transport) setupRoutes() {
 	mx := http.NewServeMux()
 
+	mx.HandleFunc("/healthz", t.healthz)
+	mx.HandleFunc("/readyz", t.readyz)
+
 	mx.HandleFunc("/add_new_chat", t.addNewChat)
 	mx.HandleFunc("/get_chats", t.getChatsInfoByOwnerId)
 	mx.HandleFunc("/disable_chat", t.disableChat)
@@ -55,17 +73,141 @@ func (t *transport) setupRoutes() {
 	mx.HandleFunc("/is_subscribe_exist", t.isSubscribeExists)
 	mx.HandleFunc("/is_paid", t.isPaid)
 
-	t.router.Handler = mx
+	mx.HandleFunc("/v1/chats/trial", t.changeTrial)
+	mx.HandleFunc("/v1/chats/price_history", t.getPriceHistory)
+	mx.HandleFunc("/v1/chats/status", t.changeChatStatus)
+	mx.HandleFunc("/v1/chats/catalog", t.changeCatalogInfo)
+	mx.HandleFunc("/v1/chats/rating", t.changeRating)
+	mx.HandleFunc("/v1/chats/rating_history", t.getRatingHistory)
+	mx.HandleFunc("/v1/access", t.getAccess)
+	mx.HandleFunc("/v1/users/age_verification", t.operatorOnly(t.setAgeVerified))
+
+	mx.HandleFunc("/v1/catalog", t.getCatalog)
+
+	mx.HandleFunc("/v1/members/add", t.addChatMember)
+	mx.HandleFunc("/v1/members/list", t.getChatMembers)
+	mx.HandleFunc("/v1/members/remove", t.removeChatMember)
+
+	mx.HandleFunc("/v1/transfers/initiate", t.initiateTransfer)
+	mx.HandleFunc("/v1/transfers/accept", t.acceptTransfer)
+	mx.HandleFunc("/v1/transfers/cancel", t.cancelTransfer)
+	mx.HandleFunc("/v1/transfers/pending", t.getPendingTransfers)
+
+	mx.HandleFunc("/v1/webhooks/add", t.addWebhook)
+	mx.HandleFunc("/v1/webhooks/list", t.getWebhooks)
+	mx.HandleFunc("/v1/webhooks/delete", t.deleteWebhook)
+	mx.HandleFunc("/v1/webhooks/secret", t.getWebhookSecret)
+	mx.HandleFunc("/v1/webhooks/deliveries", t.getWebhookDeliveries)
+	mx.HandleFunc("/v1/webhooks/redeliver", t.redeliverWebhook)
+
+	mx.HandleFunc("/v1/events/stream", t.streamEvents)
+
+	mx.HandleFunc("/v1/telegram/join_request", t.joinRequest)
+
+	mx.HandleFunc("/v1/plans/add", t.addPlan)
+	mx.HandleFunc("/v1/plans/list", t.getPlans)
+	mx.HandleFunc("/v1/plans/update", t.updatePlan)
+	mx.HandleFunc("/v1/plans/delete", t.deletePlan)
+
+	mx.HandleFunc("/v1/promo_codes/add", t.addPromoCode)
+	mx.HandleFunc("/v1/promo_codes/list", t.getPromoCodes)
+	mx.HandleFunc("/v1/promo_codes/delete", t.deletePromoCode)
+	mx.HandleFunc("/v1/quote", t.getQuote)
+
+	mx.HandleFunc("/v1/stars/invoice", t.createStarsInvoice)
+	mx.HandleFunc("/v1/stars/pre_checkout", t.preCheckout)
+	mx.HandleFunc("/v1/stars/successful_payment", t.successfulPayment)
+
+	mx.HandleFunc("/v1/refunds/add", t.refund)
+	mx.HandleFunc("/v1/refunds/list", t.getRefunds)
+	mx.HandleFunc("/v1/refunds/resolve", t.operatorOnly(t.resolveRefund))
+
+	mx.HandleFunc("/v1/owners/balance", t.getBalances)
+	mx.HandleFunc("/v1/owners/statement", t.getStatement)
+	mx.HandleFunc(ownersPrefix, t.ownerRoutes)
+	mx.HandleFunc("/v1/payouts/request", t.requestPayout)
+	mx.HandleFunc("/v1/payouts/list", t.getPayouts)
+	mx.HandleFunc("/v1/payouts/status", t.operatorOnly(t.updatePayoutStatus))
+
+	mx.HandleFunc("/v1/exports/subscribers", t.exportSubscribers)
+	mx.HandleFunc("/v1/exports/payments", t.exportPayments)
+
+	mx.HandleFunc("/v1/invites/get", t.getInviteToken)
+	mx.HandleFunc("/v1/invites/redeem", t.redeemInvite)
+
+	t.router.Handler = withRequestContext(mx)
+}
+
+func withRequestContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(service.NewRequestContext(r.Context())))
+	})
+}
+
+// operatorOnly lets through requests bearing the operator token. Without a
+// configured token the endpoint does not exist.
+func (t *transport) operatorOnly(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if t.cfg.OperatorToken == "" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(t.cfg.OperatorToken)) != 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			w.Write([]byte(`{"error": "operator token is required"}`))
+
+			return
+		}
+
+		next(w, r)
+	}
 }
 
 func (t *transport) Run() error {
-	if err := t.router.ListenAndServe(); err != nil {
+	if err := t.router.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return fmt.Errorf("failed to listen and serve. %w", err)
 	}
 
 	return nil
 }
 
+// Close marks the transport as not ready, waits for the orchestrator to stop
+// routing traffic, then shuts the server down and closes the service.
 func (t *transport) Close() error {
-	return nil
+	if !t.shuttingDown.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	// Streams are ended first so their clients reconnect to another instance.
+	close(t.done)
+
+	if t.cfg.ShutdownDelay > 0 {
+		logger.GetLogger().Info().Dur("delay", t.cfg.ShutdownDelay).Msg("draining traffic before shutdown")
+
+		time.Sleep(t.cfg.ShutdownDelay)
+	}
+
+	timeout := t.cfg.ShutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var errs []error
+
+	if err := t.router.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shutdown server. %w", err))
+	}
+
+	if err := t.service.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close service. %w", err))
+	}
+
+	return errors.Join(errs...)
 }
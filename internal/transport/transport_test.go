@@ -0,0 +1,46 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"project/internal/config"
+)
+
+func TestOperatorOnly(t *testing.T) {
+	tests := []struct {
+		name          string
+		operatorToken string
+		header        string
+		want          int
+	}{
+		{name: "disabled", header: "Bearer ", want: http.StatusNotFound},
+		{name: "missing token", operatorToken: "secret", want: http.StatusUnauthorized},
+		{name: "wrong token", operatorToken: "secret", header: "Bearer other", want: http.StatusUnauthorized},
+		{name: "not bearer", operatorToken: "secret", header: "secret", want: http.StatusUnauthorized},
+		{name: "ok", operatorToken: "secret", header: "Bearer secret", want: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &transport{cfg: config.Transport{OperatorToken: tt.operatorToken}}
+
+			h := tr.operatorOnly(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})
+
+			r := httptest.NewRequest(http.MethodPost, "/v1/payouts/status", nil)
+			if tt.header != "" {
+				r.Header.Set("Authorization", tt.header)
+			}
+
+			w := httptest.NewRecorder()
+			h(w, r)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
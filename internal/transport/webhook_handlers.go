@@ -0,0 +1,161 @@
+package transport
+
+import (
+	"net/http"
+	"project/internal/logger"
+	"project/internal/model"
+)
+
+func (t *transport) addWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.AddWebhook](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	webhook, err := t.service.AddWebhook(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to add webhook")
+
+		writeError(w, err, "failed to add webhook")
+
+		return
+	}
+
+	writeJSON(w, webhook, "failed to add webhook")
+}
+
+func (t *transport) getWebhooks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.ChatActor](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	webhooks, err := t.service.GetWebhooks(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get webhooks")
+
+		writeError(w, err, "failed to get webhooks")
+
+		return
+	}
+
+	writeJSON(w, webhooks, "failed to get webhooks")
+}
+
+func (t *transport) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.WebhookId](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	if err := t.service.DeleteWebhook(r.Context(), req.Id, req.OwnerId); err != nil {
+		logger.GetLogger().Err(err).Msg("failed to delete webhook")
+
+		writeError(w, err, "failed to delete webhook")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (t *transport) getWebhookSecret(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.OwnerSecret](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	secret, err := t.service.GetOwnerSecret(r.Context(), req)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get webhook secret")
+
+		writeError(w, err, "failed to get webhook secret")
+
+		return
+	}
+
+	writeJSON(w, model.WebhookSecret{Secret: secret}, "failed to get webhook secret")
+}
+
+func (t *transport) getWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.GetWebhookDeliveries](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	deliveries, err := t.service.GetWebhookDeliveries(r.Context(), req.WebhookId, req.OwnerId, req.Limit)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to get webhook deliveries")
+
+		writeError(w, err, "failed to get webhook deliveries")
+
+		return
+	}
+
+	writeJSON(w, deliveries, "failed to get webhook deliveries")
+}
+
+func (t *transport) redeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	req, err := unmarshalData[model.Redeliver](w, r)
+	if err != nil {
+		logger.GetLogger().Err(err).Msg("failed to unmarshalData")
+
+		return
+	}
+
+	if err := t.service.Redeliver(r.Context(), req.DeliveryId, req.OwnerId); err != nil {
+		logger.GetLogger().Err(err).Msg("failed to redeliver webhook")
+
+		writeError(w, err, "failed to redeliver webhook")
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
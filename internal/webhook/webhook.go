@@ -0,0 +1,196 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"project/internal/config"
+	"project/internal/logger"
+	"project/internal/model"
+	"project/internal/repo"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 50
+	defaultMaxAttempts  = 12
+	defaultTimeout      = 10 * time.Second
+
+	retryBaseDelay = 10 * time.Second
+	retryMaxDelay  = 6 * time.Hour
+
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventIdHeader   = "X-Webhook-Event-Id"
+)
+
+// Sign returns hex encoded HMAC-SHA256 of "<timestamp>.<body>" keyed by the
+// owner secret. Receivers recompute it to verify the payload.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Deliverer sends queued webhook deliveries. It implements worker.Worker.
+type Deliverer struct {
+	repo        repo.Repo
+	client      *http.Client
+	interval    time.Duration
+	batch       int
+	maxAttempts int
+}
+
+func NewDeliverer(r repo.Repo, cfg config.Webhooks) *Deliverer {
+	d := &Deliverer{
+		repo:        r,
+		interval:    cfg.PollInterval,
+		batch:       cfg.BatchSize,
+		maxAttempts: cfg.MaxAttempts,
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	d.client = &http.Client{Timeout: timeout}
+
+	if d.interval <= 0 {
+		d.interval = defaultPollInterval
+	}
+
+	if d.batch <= 0 {
+		d.batch = defaultBatchSize
+	}
+
+	if d.maxAttempts <= 0 {
+		d.maxAttempts = defaultMaxAttempts
+	}
+
+	return d
+}
+
+func (d *Deliverer) Name() string {
+	return "webhook-deliverer"
+}
+
+func (d *Deliverer) Run(ctx context.Context) error {
+	t := time.NewTicker(d.interval)
+	defer t.Stop()
+
+	for {
+		if err := d.deliverDue(ctx); err != nil && ctx.Err() == nil {
+			logger.GetLogger().Err(err).Msg("failed to deliver webhooks")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (d *Deliverer) deliverDue(ctx context.Context) error {
+	// Lease covers one request timeout per delivery in the batch plus slack.
+	lease := d.client.Timeout*time.Duration(d.batch) + time.Minute
+
+	deliveries, err := d.repo.ClaimWebhookDeliveries(ctx, d.batch, lease)
+	if err != nil {
+		return fmt.Errorf("failed to claim deliveries. %w", err)
+	}
+
+	for _, delivery := range deliveries {
+		attempt := d.send(ctx, delivery)
+
+		status := model.WebhookDeliveryDelivered
+		next := attempt.AttemptedAt
+
+		if attempt.Error != "" {
+			status = model.WebhookDeliveryPending
+			next = attempt.AttemptedAt.Add(backoff(delivery.Attempts))
+
+			if delivery.Attempts+1 >= d.maxAttempts {
+				status = model.WebhookDeliveryFailed
+			}
+		}
+
+		if err := d.repo.RecordWebhookAttempt(ctx, delivery.Id, attempt, status, next); err != nil {
+			return fmt.Errorf("failed to record attempt. %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (d *Deliverer) send(ctx context.Context, delivery model.PendingWebhookDelivery) (attempt model.WebhookAttempt) {
+	attempt.AttemptedAt = time.Now()
+
+	defer func() {
+		attempt.DurationMs = int(time.Since(attempt.AttemptedAt).Milliseconds())
+	}()
+
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		attempt.Error = fmt.Sprintf("failed to marshal. %v", err)
+
+		return attempt
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = fmt.Sprintf("failed to create request. %v", err)
+
+		return attempt
+	}
+
+	timestamp := attempt.AttemptedAt.Unix()
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(EventIdHeader, strconv.FormatInt(delivery.Event.Id, 10))
+	req.Header.Set(SignatureHeader, "sha256="+Sign(delivery.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		attempt.Error = fmt.Sprintf("failed to send request. %v", err)
+
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	attempt.ResponseStatus = resp.StatusCode
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		attempt.Error = fmt.Sprintf("unexpected status %d", resp.StatusCode)
+	}
+
+	return attempt
+}
+
+// backoff returns delay before the next try after attempts failed tries.
+func backoff(attempts int) time.Duration {
+	delay := float64(retryBaseDelay) * math.Pow(2, float64(attempts))
+
+	if delay > float64(retryMaxDelay) {
+		return retryMaxDelay
+	}
+
+	return time.Duration(delay)
+}
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"project/internal/logger"
+)
+
+type periodic struct {
+	name   string
+	period time.Duration
+	fn     func(context.Context) error
+}
+
+// NewPeriodic returns worker that calls fn every period. Errors from fn are
+// logged and do not stop the worker.
+func NewPeriodic(name string, period time.Duration, fn func(context.Context) error) Worker {
+	return &periodic{
+		name:   name,
+		period: period,
+		fn:     fn,
+	}
+}
+
+func (p *periodic) Name() string {
+	return p.name
+}
+
+func (p *periodic) Run(ctx context.Context) error {
+	t := time.NewTicker(p.period)
+	defer t.Stop()
+
+	for {
+		if err := p.fn(ctx); err != nil && ctx.Err() == nil {
+			logger.GetLogger().Err(err).Str("worker", p.name).Msg("periodic job failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
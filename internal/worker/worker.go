@@ -0,0 +1,114 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"project/internal/logger"
+)
+
+const restartDelay = 5 * time.Second
+
+// Worker is a long running background job.
+type Worker interface {
+	Name() string
+	Run(context.Context) error
+}
+
+// Group runs workers until it is stopped and reports which of them are alive.
+type Group struct {
+	mu      sync.Mutex
+	workers []Worker
+	running map[string]bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+func NewGroup() *Group {
+	return &Group{
+		running: make(map[string]bool),
+	}
+}
+
+// Add registers worker. Workers added after Start are not run.
+func (g *Group) Add(w Worker) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.workers = append(g.workers, w)
+	g.running[w.Name()] = false
+}
+
+// Start runs every registered worker in its own goroutine. A worker that
+// returns before the group is stopped is restarted after a short delay.
+func (g *Group) Start(ctx context.Context) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	ctx, g.cancel = context.WithCancel(ctx)
+
+	for _, w := range g.workers {
+		g.wg.Add(1)
+
+		go g.run(ctx, w)
+	}
+}
+
+func (g *Group) run(ctx context.Context, w Worker) {
+	defer g.wg.Done()
+
+	for {
+		g.setRunning(w.Name(), true)
+
+		err := w.Run(ctx)
+
+		g.setRunning(w.Name(), false)
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		logger.GetLogger().Err(err).Str("worker", w.Name()).Msg("worker stopped, restarting")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(restartDelay):
+		}
+	}
+}
+
+func (g *Group) setRunning(name string, running bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.running[name] = running
+}
+
+// Status returns running state of every registered worker.
+func (g *Group) Status() map[string]bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	res := make(map[string]bool, len(g.running))
+
+	for name, running := range g.running {
+		res[name] = running
+	}
+
+	return res
+}
+
+// Stop cancels all workers and waits for them to return.
+func (g *Group) Stop() {
+	g.mu.Lock()
+	cancel := g.cancel
+	g.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	g.wg.Wait()
+}